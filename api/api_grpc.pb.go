@@ -27,13 +27,63 @@ type LinkGraphClient interface {
 	UpsertLink(ctx context.Context, in *Link, opts ...grpc.CallOption) (*Link, error)
 	// UpsertEdge inserts or updates an edge.
 	UpsertEdge(ctx context.Context, in *Edge, opts ...grpc.CallOption) (*Edge, error)
-	// Links streams the set of links in the specified ID range.
+	// UpsertLinks inserts or updates the streamed links as a single batch.
+	UpsertLinks(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertLinksClient, error)
+	// UpsertEdges inserts or updates the streamed edges as a single batch.
+	UpsertEdges(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertEdgesClient, error)
+	// LookupLink returns the link with the specified ID, or a NotFound
+	// status if there is no such link.
+	LookupLink(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Link, error)
+	// LookupLinkByURL returns the link with the specified URL, or a NotFound
+	// status if there is no such link.
+	LookupLinkByURL(ctx context.Context, in *LookupLinkByURLQuery, opts ...grpc.CallOption) (*Link, error)
+	// ResolveURLs returns the link ID of every known URL in the query.
+	ResolveURLs(ctx context.Context, in *ResolveURLsQuery, opts ...grpc.CallOption) (*ResolveURLsResult, error)
+	// LookupEdge returns the edge with the specified ID, or a NotFound
+	// status if there is no such edge.
+	LookupEdge(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Edge, error)
+	// UpsertRedirect inserts or replaces the redirect of its alias.
+	UpsertRedirect(ctx context.Context, in *Redirect, opts ...grpc.CallOption) (*Redirect, error)
+	// ResolveRedirect returns the chain of redirects that start at the
+	// specified link, or a FailedPrecondition status if the chain loops.
+	ResolveRedirect(ctx context.Context, in *ID, opts ...grpc.CallOption) (*RedirectChain, error)
+	// UpdateScores stores the scores of the specified links, unknown links
+	// are ignored.
+	UpdateScores(ctx context.Context, in *UpdateScoresRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// ComputeScores starts a job computing the scores of every link and
+	// returns without waiting for it, or a ResourceExhausted status if a job
+	// is already running.
+	ComputeScores(ctx context.Context, in *ComputeScoresRequest, opts ...grpc.CallOption) (*ScoreJob, error)
+	// GetScoreJob returns the progress of the job with the specified ID, or a
+	// NotFound status if there is no such job.
+	GetScoreJob(ctx context.Context, in *ID, opts ...grpc.CallOption) (*ScoreJob, error)
+	// Links streams the set of links in the specified ID range, ordered by ID.
+	// With name-based IDs it includes placeholder links, which have an empty
+	// url and no retrieved_at until the link itself is upserted.
 	Links(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_LinksClient, error)
-	// Edges streams the set of edges in the specified ID range.
+	// Edges streams the set of edges whose source is in the specified ID
+	// range, ordered by source and destination ID.
 	Edges(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_EdgesClient, error)
+	// OutEdges streams the edges that originate from the specified link,
+	// ordered by destination ID.
+	OutEdges(ctx context.Context, in *ID, opts ...grpc.CallOption) (LinkGraph_OutEdgesClient, error)
+	// InEdges streams the edges that end at the specified link, ordered by
+	// source ID.
+	InEdges(ctx context.Context, in *ID, opts ...grpc.CallOption) (LinkGraph_InEdgesClient, error)
 	// RemoveStaleEdges removes any edge that originates from the specified
-	// link ID and was updated before the specified timestamp.
-	RemoveStaleEdges(ctx context.Context, in *RemoveStaleEdgesQuery, opts ...grpc.CallOption) (*empty.Empty, error)
+	// link ID and was updated before the specified timestamp. It returns the
+	// number of removed edges and their destination link IDs; with dry_run set
+	// it reports the edges that would be removed without removing them.
+	RemoveStaleEdges(ctx context.Context, in *RemoveStaleEdgesQuery, opts ...grpc.CallOption) (*RemoveStaleEdgesResult, error)
+	// RemoveLink removes the link with the specified ID and its edges, or
+	// returns a NotFound status if there is no such link.
+	RemoveLink(ctx context.Context, in *ID, opts ...grpc.CallOption) (*empty.Empty, error)
+	// RemoveStaleLinks removes any link, and its edges, that was retrieved
+	// before the specified timestamp.
+	RemoveStaleLinks(ctx context.Context, in *RemoveLinksQuery, opts ...grpc.CallOption) (*RemoveLinksResult, error)
+	// RemoveOrphanLinks removes any link without incoming or outgoing edges
+	// that was retrieved before the specified timestamp.
+	RemoveOrphanLinks(ctx context.Context, in *RemoveLinksQuery, opts ...grpc.CallOption) (*RemoveLinksResult, error)
 }
 
 type linkGraphClient struct {
@@ -62,8 +112,157 @@ func (c *linkGraphClient) UpsertEdge(ctx context.Context, in *Edge, opts ...grpc
 	return out, nil
 }
 
+func (c *linkGraphClient) UpsertLinks(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertLinksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[0], "/proto.LinkGraph/UpsertLinks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphUpsertLinksClient{stream}
+	return x, nil
+}
+
+type LinkGraph_UpsertLinksClient interface {
+	Send(*Link) error
+	CloseAndRecv() (*UpsertLinksResult, error)
+	grpc.ClientStream
+}
+
+type linkGraphUpsertLinksClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphUpsertLinksClient) Send(m *Link) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertLinksClient) CloseAndRecv() (*UpsertLinksResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UpsertLinksResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) UpsertEdges(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertEdgesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[1], "/proto.LinkGraph/UpsertEdges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphUpsertEdgesClient{stream}
+	return x, nil
+}
+
+type LinkGraph_UpsertEdgesClient interface {
+	Send(*Edge) error
+	CloseAndRecv() (*UpsertEdgesResult, error)
+	grpc.ClientStream
+}
+
+type linkGraphUpsertEdgesClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphUpsertEdgesClient) Send(m *Edge) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertEdgesClient) CloseAndRecv() (*UpsertEdgesResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UpsertEdgesResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) LookupLink(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Link, error) {
+	out := new(Link)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/LookupLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) LookupLinkByURL(ctx context.Context, in *LookupLinkByURLQuery, opts ...grpc.CallOption) (*Link, error) {
+	out := new(Link)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/LookupLinkByURL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) ResolveURLs(ctx context.Context, in *ResolveURLsQuery, opts ...grpc.CallOption) (*ResolveURLsResult, error) {
+	out := new(ResolveURLsResult)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/ResolveURLs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) LookupEdge(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Edge, error) {
+	out := new(Edge)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/LookupEdge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) UpsertRedirect(ctx context.Context, in *Redirect, opts ...grpc.CallOption) (*Redirect, error) {
+	out := new(Redirect)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/UpsertRedirect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) ResolveRedirect(ctx context.Context, in *ID, opts ...grpc.CallOption) (*RedirectChain, error) {
+	out := new(RedirectChain)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/ResolveRedirect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) UpdateScores(ctx context.Context, in *UpdateScoresRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/UpdateScores", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) ComputeScores(ctx context.Context, in *ComputeScoresRequest, opts ...grpc.CallOption) (*ScoreJob, error) {
+	out := new(ScoreJob)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/ComputeScores", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) GetScoreJob(ctx context.Context, in *ID, opts ...grpc.CallOption) (*ScoreJob, error) {
+	out := new(ScoreJob)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/GetScoreJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *linkGraphClient) Links(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_LinksClient, error) {
-	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[0], "/proto.LinkGraph/Links", opts...)
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[2], "/proto.LinkGraph/Links", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +294,7 @@ func (x *linkGraphLinksClient) Recv() (*Link, error) {
 }
 
 func (c *linkGraphClient) Edges(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_EdgesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[1], "/proto.LinkGraph/Edges", opts...)
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[3], "/proto.LinkGraph/Edges", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -126,8 +325,72 @@ func (x *linkGraphEdgesClient) Recv() (*Edge, error) {
 	return m, nil
 }
 
-func (c *linkGraphClient) RemoveStaleEdges(ctx context.Context, in *RemoveStaleEdgesQuery, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
+func (c *linkGraphClient) OutEdges(ctx context.Context, in *ID, opts ...grpc.CallOption) (LinkGraph_OutEdgesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[4], "/proto.LinkGraph/OutEdges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphOutEdgesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LinkGraph_OutEdgesClient interface {
+	Recv() (*Edge, error)
+	grpc.ClientStream
+}
+
+type linkGraphOutEdgesClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphOutEdgesClient) Recv() (*Edge, error) {
+	m := new(Edge)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) InEdges(ctx context.Context, in *ID, opts ...grpc.CallOption) (LinkGraph_InEdgesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[5], "/proto.LinkGraph/InEdges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphInEdgesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LinkGraph_InEdgesClient interface {
+	Recv() (*Edge, error)
+	grpc.ClientStream
+}
+
+type linkGraphInEdgesClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphInEdgesClient) Recv() (*Edge, error) {
+	m := new(Edge)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) RemoveStaleEdges(ctx context.Context, in *RemoveStaleEdgesQuery, opts ...grpc.CallOption) (*RemoveStaleEdgesResult, error) {
+	out := new(RemoveStaleEdgesResult)
 	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RemoveStaleEdges", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -135,6 +398,33 @@ func (c *linkGraphClient) RemoveStaleEdges(ctx context.Context, in *RemoveStaleE
 	return out, nil
 }
 
+func (c *linkGraphClient) RemoveLink(ctx context.Context, in *ID, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RemoveLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) RemoveStaleLinks(ctx context.Context, in *RemoveLinksQuery, opts ...grpc.CallOption) (*RemoveLinksResult, error) {
+	out := new(RemoveLinksResult)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RemoveStaleLinks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) RemoveOrphanLinks(ctx context.Context, in *RemoveLinksQuery, opts ...grpc.CallOption) (*RemoveLinksResult, error) {
+	out := new(RemoveLinksResult)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RemoveOrphanLinks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LinkGraphServer is the server API for LinkGraph service.
 // All implementations must embed UnimplementedLinkGraphServer
 // for forward compatibility
@@ -143,13 +433,63 @@ type LinkGraphServer interface {
 	UpsertLink(context.Context, *Link) (*Link, error)
 	// UpsertEdge inserts or updates an edge.
 	UpsertEdge(context.Context, *Edge) (*Edge, error)
-	// Links streams the set of links in the specified ID range.
+	// UpsertLinks inserts or updates the streamed links as a single batch.
+	UpsertLinks(LinkGraph_UpsertLinksServer) error
+	// UpsertEdges inserts or updates the streamed edges as a single batch.
+	UpsertEdges(LinkGraph_UpsertEdgesServer) error
+	// LookupLink returns the link with the specified ID, or a NotFound
+	// status if there is no such link.
+	LookupLink(context.Context, *ID) (*Link, error)
+	// LookupLinkByURL returns the link with the specified URL, or a NotFound
+	// status if there is no such link.
+	LookupLinkByURL(context.Context, *LookupLinkByURLQuery) (*Link, error)
+	// ResolveURLs returns the link ID of every known URL in the query.
+	ResolveURLs(context.Context, *ResolveURLsQuery) (*ResolveURLsResult, error)
+	// LookupEdge returns the edge with the specified ID, or a NotFound
+	// status if there is no such edge.
+	LookupEdge(context.Context, *ID) (*Edge, error)
+	// UpsertRedirect inserts or replaces the redirect of its alias.
+	UpsertRedirect(context.Context, *Redirect) (*Redirect, error)
+	// ResolveRedirect returns the chain of redirects that start at the
+	// specified link, or a FailedPrecondition status if the chain loops.
+	ResolveRedirect(context.Context, *ID) (*RedirectChain, error)
+	// UpdateScores stores the scores of the specified links, unknown links
+	// are ignored.
+	UpdateScores(context.Context, *UpdateScoresRequest) (*empty.Empty, error)
+	// ComputeScores starts a job computing the scores of every link and
+	// returns without waiting for it, or a ResourceExhausted status if a job
+	// is already running.
+	ComputeScores(context.Context, *ComputeScoresRequest) (*ScoreJob, error)
+	// GetScoreJob returns the progress of the job with the specified ID, or a
+	// NotFound status if there is no such job.
+	GetScoreJob(context.Context, *ID) (*ScoreJob, error)
+	// Links streams the set of links in the specified ID range, ordered by ID.
+	// With name-based IDs it includes placeholder links, which have an empty
+	// url and no retrieved_at until the link itself is upserted.
 	Links(*Range, LinkGraph_LinksServer) error
-	// Edges streams the set of edges in the specified ID range.
+	// Edges streams the set of edges whose source is in the specified ID
+	// range, ordered by source and destination ID.
 	Edges(*Range, LinkGraph_EdgesServer) error
+	// OutEdges streams the edges that originate from the specified link,
+	// ordered by destination ID.
+	OutEdges(*ID, LinkGraph_OutEdgesServer) error
+	// InEdges streams the edges that end at the specified link, ordered by
+	// source ID.
+	InEdges(*ID, LinkGraph_InEdgesServer) error
 	// RemoveStaleEdges removes any edge that originates from the specified
-	// link ID and was updated before the specified timestamp.
-	RemoveStaleEdges(context.Context, *RemoveStaleEdgesQuery) (*empty.Empty, error)
+	// link ID and was updated before the specified timestamp. It returns the
+	// number of removed edges and their destination link IDs; with dry_run set
+	// it reports the edges that would be removed without removing them.
+	RemoveStaleEdges(context.Context, *RemoveStaleEdgesQuery) (*RemoveStaleEdgesResult, error)
+	// RemoveLink removes the link with the specified ID and its edges, or
+	// returns a NotFound status if there is no such link.
+	RemoveLink(context.Context, *ID) (*empty.Empty, error)
+	// RemoveStaleLinks removes any link, and its edges, that was retrieved
+	// before the specified timestamp.
+	RemoveStaleLinks(context.Context, *RemoveLinksQuery) (*RemoveLinksResult, error)
+	// RemoveOrphanLinks removes any link without incoming or outgoing edges
+	// that was retrieved before the specified timestamp.
+	RemoveOrphanLinks(context.Context, *RemoveLinksQuery) (*RemoveLinksResult, error)
 	mustEmbedUnimplementedLinkGraphServer()
 }
 
@@ -163,15 +503,63 @@ func (UnimplementedLinkGraphServer) UpsertLink(context.Context, *Link) (*Link, e
 func (UnimplementedLinkGraphServer) UpsertEdge(context.Context, *Edge) (*Edge, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpsertEdge not implemented")
 }
+func (UnimplementedLinkGraphServer) UpsertLinks(LinkGraph_UpsertLinksServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpsertLinks not implemented")
+}
+func (UnimplementedLinkGraphServer) UpsertEdges(LinkGraph_UpsertEdgesServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpsertEdges not implemented")
+}
+func (UnimplementedLinkGraphServer) LookupLink(context.Context, *ID) (*Link, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupLink not implemented")
+}
+func (UnimplementedLinkGraphServer) LookupLinkByURL(context.Context, *LookupLinkByURLQuery) (*Link, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupLinkByURL not implemented")
+}
+func (UnimplementedLinkGraphServer) ResolveURLs(context.Context, *ResolveURLsQuery) (*ResolveURLsResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveURLs not implemented")
+}
+func (UnimplementedLinkGraphServer) LookupEdge(context.Context, *ID) (*Edge, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupEdge not implemented")
+}
+func (UnimplementedLinkGraphServer) UpsertRedirect(context.Context, *Redirect) (*Redirect, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertRedirect not implemented")
+}
+func (UnimplementedLinkGraphServer) ResolveRedirect(context.Context, *ID) (*RedirectChain, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveRedirect not implemented")
+}
+func (UnimplementedLinkGraphServer) UpdateScores(context.Context, *UpdateScoresRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateScores not implemented")
+}
+func (UnimplementedLinkGraphServer) ComputeScores(context.Context, *ComputeScoresRequest) (*ScoreJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComputeScores not implemented")
+}
+func (UnimplementedLinkGraphServer) GetScoreJob(context.Context, *ID) (*ScoreJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScoreJob not implemented")
+}
 func (UnimplementedLinkGraphServer) Links(*Range, LinkGraph_LinksServer) error {
 	return status.Errorf(codes.Unimplemented, "method Links not implemented")
 }
 func (UnimplementedLinkGraphServer) Edges(*Range, LinkGraph_EdgesServer) error {
 	return status.Errorf(codes.Unimplemented, "method Edges not implemented")
 }
-func (UnimplementedLinkGraphServer) RemoveStaleEdges(context.Context, *RemoveStaleEdgesQuery) (*empty.Empty, error) {
+func (UnimplementedLinkGraphServer) OutEdges(*ID, LinkGraph_OutEdgesServer) error {
+	return status.Errorf(codes.Unimplemented, "method OutEdges not implemented")
+}
+func (UnimplementedLinkGraphServer) InEdges(*ID, LinkGraph_InEdgesServer) error {
+	return status.Errorf(codes.Unimplemented, "method InEdges not implemented")
+}
+func (UnimplementedLinkGraphServer) RemoveStaleEdges(context.Context, *RemoveStaleEdgesQuery) (*RemoveStaleEdgesResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveStaleEdges not implemented")
 }
+func (UnimplementedLinkGraphServer) RemoveLink(context.Context, *ID) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveLink not implemented")
+}
+func (UnimplementedLinkGraphServer) RemoveStaleLinks(context.Context, *RemoveLinksQuery) (*RemoveLinksResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveStaleLinks not implemented")
+}
+func (UnimplementedLinkGraphServer) RemoveOrphanLinks(context.Context, *RemoveLinksQuery) (*RemoveLinksResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveOrphanLinks not implemented")
+}
 func (UnimplementedLinkGraphServer) mustEmbedUnimplementedLinkGraphServer() {}
 
 // UnsafeLinkGraphServer may be embedded to opt out of forward compatibility for this service.
@@ -221,6 +609,220 @@ func _LinkGraph_UpsertEdge_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LinkGraph_UpsertLinks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LinkGraphServer).UpsertLinks(&linkGraphUpsertLinksServer{stream})
+}
+
+type LinkGraph_UpsertLinksServer interface {
+	SendAndClose(*UpsertLinksResult) error
+	Recv() (*Link, error)
+	grpc.ServerStream
+}
+
+type linkGraphUpsertLinksServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphUpsertLinksServer) SendAndClose(m *UpsertLinksResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertLinksServer) Recv() (*Link, error) {
+	m := new(Link)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LinkGraph_UpsertEdges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LinkGraphServer).UpsertEdges(&linkGraphUpsertEdgesServer{stream})
+}
+
+type LinkGraph_UpsertEdgesServer interface {
+	SendAndClose(*UpsertEdgesResult) error
+	Recv() (*Edge, error)
+	grpc.ServerStream
+}
+
+type linkGraphUpsertEdgesServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphUpsertEdgesServer) SendAndClose(m *UpsertEdgesResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertEdgesServer) Recv() (*Edge, error) {
+	m := new(Edge)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LinkGraph_LookupLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).LookupLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/LookupLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).LookupLink(ctx, req.(*ID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_LookupLinkByURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupLinkByURLQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).LookupLinkByURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/LookupLinkByURL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).LookupLinkByURL(ctx, req.(*LookupLinkByURLQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_ResolveURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveURLsQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).ResolveURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/ResolveURLs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).ResolveURLs(ctx, req.(*ResolveURLsQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_LookupEdge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).LookupEdge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/LookupEdge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).LookupEdge(ctx, req.(*ID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_UpsertRedirect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Redirect)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).UpsertRedirect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/UpsertRedirect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).UpsertRedirect(ctx, req.(*Redirect))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_ResolveRedirect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).ResolveRedirect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/ResolveRedirect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).ResolveRedirect(ctx, req.(*ID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_UpdateScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).UpdateScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/UpdateScores",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).UpdateScores(ctx, req.(*UpdateScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_ComputeScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComputeScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).ComputeScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/ComputeScores",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).ComputeScores(ctx, req.(*ComputeScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_GetScoreJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).GetScoreJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/GetScoreJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).GetScoreJob(ctx, req.(*ID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LinkGraph_Links_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(Range)
 	if err := stream.RecvMsg(m); err != nil {
@@ -263,6 +865,48 @@ func (x *linkGraphEdgesServer) Send(m *Edge) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _LinkGraph_OutEdges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LinkGraphServer).OutEdges(m, &linkGraphOutEdgesServer{stream})
+}
+
+type LinkGraph_OutEdgesServer interface {
+	Send(*Edge) error
+	grpc.ServerStream
+}
+
+type linkGraphOutEdgesServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphOutEdgesServer) Send(m *Edge) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LinkGraph_InEdges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LinkGraphServer).InEdges(m, &linkGraphInEdgesServer{stream})
+}
+
+type LinkGraph_InEdgesServer interface {
+	Send(*Edge) error
+	grpc.ServerStream
+}
+
+type linkGraphInEdgesServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphInEdgesServer) Send(m *Edge) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _LinkGraph_RemoveStaleEdges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RemoveStaleEdgesQuery)
 	if err := dec(in); err != nil {
@@ -281,6 +925,60 @@ func _LinkGraph_RemoveStaleEdges_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LinkGraph_RemoveLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).RemoveLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/RemoveLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).RemoveLink(ctx, req.(*ID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_RemoveStaleLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveLinksQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).RemoveStaleLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/RemoveStaleLinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).RemoveStaleLinks(ctx, req.(*RemoveLinksQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_RemoveOrphanLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveLinksQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).RemoveOrphanLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/RemoveOrphanLinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).RemoveOrphanLinks(ctx, req.(*RemoveLinksQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LinkGraph_ServiceDesc is the grpc.ServiceDesc for LinkGraph service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -296,12 +994,70 @@ var LinkGraph_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpsertEdge",
 			Handler:    _LinkGraph_UpsertEdge_Handler,
 		},
+		{
+			MethodName: "LookupLink",
+			Handler:    _LinkGraph_LookupLink_Handler,
+		},
+		{
+			MethodName: "LookupLinkByURL",
+			Handler:    _LinkGraph_LookupLinkByURL_Handler,
+		},
+		{
+			MethodName: "ResolveURLs",
+			Handler:    _LinkGraph_ResolveURLs_Handler,
+		},
+		{
+			MethodName: "LookupEdge",
+			Handler:    _LinkGraph_LookupEdge_Handler,
+		},
+		{
+			MethodName: "UpsertRedirect",
+			Handler:    _LinkGraph_UpsertRedirect_Handler,
+		},
+		{
+			MethodName: "ResolveRedirect",
+			Handler:    _LinkGraph_ResolveRedirect_Handler,
+		},
+		{
+			MethodName: "UpdateScores",
+			Handler:    _LinkGraph_UpdateScores_Handler,
+		},
+		{
+			MethodName: "ComputeScores",
+			Handler:    _LinkGraph_ComputeScores_Handler,
+		},
+		{
+			MethodName: "GetScoreJob",
+			Handler:    _LinkGraph_GetScoreJob_Handler,
+		},
 		{
 			MethodName: "RemoveStaleEdges",
 			Handler:    _LinkGraph_RemoveStaleEdges_Handler,
 		},
+		{
+			MethodName: "RemoveLink",
+			Handler:    _LinkGraph_RemoveLink_Handler,
+		},
+		{
+			MethodName: "RemoveStaleLinks",
+			Handler:    _LinkGraph_RemoveStaleLinks_Handler,
+		},
+		{
+			MethodName: "RemoveOrphanLinks",
+			Handler:    _LinkGraph_RemoveOrphanLinks_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UpsertLinks",
+			Handler:       _LinkGraph_UpsertLinks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UpsertEdges",
+			Handler:       _LinkGraph_UpsertEdges_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "Links",
 			Handler:       _LinkGraph_Links_Handler,
@@ -312,6 +1068,16 @@ var LinkGraph_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _LinkGraph_Edges_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "OutEdges",
+			Handler:       _LinkGraph_OutEdges_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "InEdges",
+			Handler:       _LinkGraph_InEdges_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/api.proto",
 }
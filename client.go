@@ -9,8 +9,9 @@ import (
 	"github.com/odit-bit/linkstore/api"
 	"github.com/odit-bit/linkstore/linkgraph"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/grpc/status"
 )
 
 func ConnectGraph(addr string) (*apiClient, error) {
@@ -19,24 +20,25 @@ func ConnectGraph(addr string) (*apiClient, error) {
 		return nil, err
 	}
 
-	return NewClient(context.TODO(), graphConn)
+	return NewClient(graphConn)
 }
 
 var _ linkgraph.Graph = (*apiClient)(nil)
 
 type apiClient struct {
-	ctx context.Context
 	lgc api.LinkGraphClient
 }
 
 // New returns a new client instance that implements a subset
 // of the linkgraph.Graph interface by delegating methods to a graph instance
 // exposed by a remote gRPC sever.
-func NewClient(ctx context.Context, clientConn grpc.ClientConnInterface) (*apiClient, error) {
+//
+// the context given to each method is used for the RPC, so its deadline and
+// cancellation are propagated to the server.
+func NewClient(clientConn grpc.ClientConnInterface) (*apiClient, error) {
 	lgClient := api.NewLinkGraphClient(clientConn)
 
 	linkCli := apiClient{
-		ctx: ctx,
 		lgc: lgClient,
 	}
 
@@ -44,20 +46,30 @@ func NewClient(ctx context.Context, clientConn grpc.ClientConnInterface) (*apiCl
 }
 
 // Edges implements linkgraph.Graph.
-func (cli *apiClient) Edges(fromID uuid.UUID, toID uuid.UUID, updateBefore time.Time) (linkgraph.EdgeIterator, error) {
+func (cli *apiClient) Edges(ctx context.Context, fromID uuid.UUID, toID uuid.UUID, updateBefore time.Time, opts ...linkgraph.EdgeOption) (linkgraph.EdgeIterator, error) {
+	o := linkgraph.NewEdgeOptions(opts...)
 	r := api.Range{
-		FromUuid: fromID[:],
-		ToUuid:   toID[:],
-		Filter:   timestamppb.New(updateBefore),
+		FromUuid:     fromID[:],
+		ToUuid:       toID[:],
+		Filter:       timeToProto(updateBefore),
+		SkipNofollow: o.SkipNofollow,
+		AsOf:         timeToProto(o.AsOf),
+
+		ResolveRedirects: o.ResolveRedirects,
 	}
 
-	ctx, cancel := context.WithCancel(cli.ctx)
+	ctx, cancel := context.WithCancel(ctx)
 	stream, err := cli.lgc.Edges(ctx, &r)
 	if err != nil {
 		cancel()
-		return nil, err
+		return nil, fromStatus(err)
 	}
 	return &edgeIterator{
+		ctx: ctx,
+		reopen: func() (api.LinkGraph_EdgesClient, error) {
+			return cli.lgc.Edges(ctx, &r)
+		},
+		r:        &r,
 		stream:   stream,
 		edge:     nil,
 		err:      nil,
@@ -66,21 +78,26 @@ func (cli *apiClient) Edges(fromID uuid.UUID, toID uuid.UUID, updateBefore time.
 }
 
 // Links implements linkgraph.Graph.
-func (cli *apiClient) Links(fromID uuid.UUID, toID uuid.UUID, retrieveBefore time.Time) (linkgraph.LinkIterator, error) {
-	ctx, cancel := context.WithCancel(cli.ctx)
+func (cli *apiClient) Links(ctx context.Context, fromID uuid.UUID, toID uuid.UUID, retrieveBefore time.Time) (linkgraph.LinkIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
 	r := api.Range{
 		FromUuid: fromID[:],
 		ToUuid:   toID[:],
-		Filter:   timestamppb.New(retrieveBefore),
+		Filter:   timeToProto(retrieveBefore),
 	}
 	stream, err := cli.lgc.Links(ctx, &r)
 	if err != nil {
 		cancel()
-		return nil, err
+		return nil, fromStatus(err)
 	}
 
 	//make linkIterator instance
 	return &linkIterator{
+		ctx: ctx,
+		reopen: func() (api.LinkGraph_LinksClient, error) {
+			return cli.lgc.Links(ctx, &r)
+		},
+		r:        &r,
 		stream:   stream,
 		link:     nil,
 		err:      nil,
@@ -88,60 +105,337 @@ func (cli *apiClient) Links(fromID uuid.UUID, toID uuid.UUID, retrieveBefore tim
 	}, nil
 }
 
+// OutEdges implements linkgraph.Graph.
+func (cli *apiClient) OutEdges(ctx context.Context, linkID uuid.UUID) (linkgraph.EdgeIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := cli.lgc.OutEdges(ctx, &api.ID{Uuid: linkID[:]})
+	if err != nil {
+		cancel()
+		return nil, fromStatus(err)
+	}
+
+	// the stream can't be resumed so it is never reopened
+	return &edgeIterator{
+		ctx:      ctx,
+		stream:   stream,
+		cancelFn: cancel,
+	}, nil
+}
+
+// InEdges implements linkgraph.Graph.
+func (cli *apiClient) InEdges(ctx context.Context, linkID uuid.UUID) (linkgraph.EdgeIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := cli.lgc.InEdges(ctx, &api.ID{Uuid: linkID[:]})
+	if err != nil {
+		cancel()
+		return nil, fromStatus(err)
+	}
+
+	return &edgeIterator{
+		ctx:      ctx,
+		stream:   stream,
+		cancelFn: cancel,
+	}, nil
+}
+
 // RemoveStaleEdges implements linkgraph.Graph.
-func (cli *apiClient) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error {
-	ctx, cancel := context.WithCancel(cli.ctx)
-	defer cancel()
-	_, err := cli.lgc.RemoveStaleEdges(ctx, &api.RemoveStaleEdgesQuery{
+func (cli *apiClient) RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time, opts ...linkgraph.RemoveOption) (*linkgraph.RemovedEdges, error) {
+	res, err := cli.lgc.RemoveStaleEdges(ctx, &api.RemoveStaleEdgesQuery{
 		FromUuid:      fromID[:],
-		UpdatedBefore: timestamppb.New(updatedBefore),
+		UpdatedBefore: timeToProto(updatedBefore),
+		DryRun:        linkgraph.NewRemoveOptions(opts...).DryRun,
 	})
 	if err != nil {
-		return err
+		return nil, fromStatus(err)
+	}
+
+	removed := linkgraph.RemovedEdges{
+		Count:  res.Removed,
+		DstIDs: make([]uuid.UUID, len(res.DstUuids)),
+	}
+	for i, id := range res.DstUuids {
+		removed.DstIDs[i] = uuidFromBytes(id)
+	}
+	return &removed, nil
+}
+
+// RemoveLink implements linkgraph.Graph.
+func (cli *apiClient) RemoveLink(ctx context.Context, id uuid.UUID) error {
+	if _, err := cli.lgc.RemoveLink(ctx, &api.ID{Uuid: id[:]}); err != nil {
+		return fromStatus(err)
 	}
 	return nil
 }
 
+// RemoveStaleLinks implements linkgraph.Graph.
+func (cli *apiClient) RemoveStaleLinks(ctx context.Context, retrievedBefore time.Time) (int64, error) {
+	res, err := cli.lgc.RemoveStaleLinks(ctx, &api.RemoveLinksQuery{RetrievedBefore: timeToProto(retrievedBefore)})
+	if err != nil {
+		return 0, fromStatus(err)
+	}
+	return res.Removed, nil
+}
+
+// RemoveOrphanLinks implements linkgraph.Graph.
+func (cli *apiClient) RemoveOrphanLinks(ctx context.Context, retrievedBefore time.Time) (int64, error) {
+	res, err := cli.lgc.RemoveOrphanLinks(ctx, &api.RemoveLinksQuery{RetrievedBefore: timeToProto(retrievedBefore)})
+	if err != nil {
+		return 0, fromStatus(err)
+	}
+	return res.Removed, nil
+}
+
+// LookupLink implements linkgraph.Graph.
+func (cli *apiClient) LookupLink(ctx context.Context, id uuid.UUID) (*linkgraph.Link, error) {
+	rpcLink, err := cli.lgc.LookupLink(ctx, &api.ID{Uuid: id[:]})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+
+	return linkFromProto(rpcLink), nil
+}
+
+// LookupLinkByURL implements linkgraph.Graph.
+func (cli *apiClient) LookupLinkByURL(ctx context.Context, url string) (*linkgraph.Link, error) {
+	rpcLink, err := cli.lgc.LookupLinkByURL(ctx, &api.LookupLinkByURLQuery{Url: url})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+
+	return linkFromProto(rpcLink), nil
+}
+
+// ResolveURLs implements linkgraph.Graph.
+func (cli *apiClient) ResolveURLs(ctx context.Context, urls []string) (map[string]uuid.UUID, error) {
+	res, err := cli.lgc.ResolveURLs(ctx, &api.ResolveURLsQuery{Urls: urls})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+
+	resolved := make(map[string]uuid.UUID, len(res.Uuids))
+	for url, id := range res.Uuids {
+		resolved[url] = uuidFromBytes(id)
+	}
+	return resolved, nil
+}
+
+// LookupEdge implements linkgraph.Graph.
+func (cli *apiClient) LookupEdge(ctx context.Context, id uuid.UUID) (*linkgraph.Edge, error) {
+	rpcEdge, err := cli.lgc.LookupEdge(ctx, &api.ID{Uuid: id[:]})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+
+	return edgeFromProto(rpcEdge), nil
+}
+
 // UpsertEdge implements linkgraph.Graph.
-func (cli *apiClient) UpsertEdge(edge *linkgraph.Edge) error {
+func (cli *apiClient) UpsertEdge(ctx context.Context, edge *linkgraph.Edge) error {
 
-	rpcEdge, err := cli.lgc.UpsertEdge(cli.ctx, &api.Edge{
-		Uuid:      edge.ID[:],
-		SrcUuid:   edge.Src[:],
-		DstUuid:   edge.Dst[:],
-		UpdatedAt: timestamppb.New(edge.UpdateAt),
-	})
+	rpcEdge, err := cli.lgc.UpsertEdge(ctx, edgeToProto(edge))
+	if err != nil {
+		return fromStatus(err)
+	}
+
+	*edge = *edgeFromProto(rpcEdge)
+	return nil
+}
+
+// scoreBatchSize is the number of scores sent per UpdateScores request, so
+// large update stay below the message size limit.
+const scoreBatchSize = 10000
+
+// UpdateScores implements linkgraph.Graph.
+func (cli *apiClient) UpdateScores(ctx context.Context, kind linkgraph.ScoreKind, scores map[uuid.UUID]float64) error {
+	req := api.UpdateScoresRequest{Kind: api.ScoreKind(kind)}
+	send := func() error {
+		_, err := cli.lgc.UpdateScores(ctx, &req)
+		req.Uuids, req.Scores = req.Uuids[:0], req.Scores[:0]
+		return fromStatus(err)
+	}
+
+	for id, score := range scores {
+		id := id
+		req.Uuids = append(req.Uuids, id[:])
+		req.Scores = append(req.Scores, score)
+		if len(req.Uuids) == scoreBatchSize {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// an empty request still validate the kind
+	if len(req.Uuids) > 0 || len(scores) == 0 {
+		return send()
+	}
+	return nil
+}
+
+// ComputeScores start a job computing the scores of every link on the server
+// and return without waiting for it, use ScoreJob to follow its progress.
+// ErrJobRunning is returned while another job is running.
+func (cli *apiClient) ComputeScores(ctx context.Context, spec ScoreJobSpec) (*ScoreJob, error) {
+	req := api.ComputeScoresRequest{
+		Kind:          api.ScoreKind(spec.Kind),
+		DampingFactor: spec.DampingFactor,
+		Tolerance:     spec.Tolerance,
+		MaxIterations: int32(spec.MaxIterations),
+	}
+	for _, id := range spec.Seeds {
+		id := id
+		req.SeedUuids = append(req.SeedUuids, id[:])
+	}
+
+	job, err := cli.lgc.ComputeScores(ctx, &req)
+	if err != nil {
+		if status.Code(err) == codes.ResourceExhausted {
+			return nil, ErrJobRunning
+		}
+		return nil, fromStatus(err)
+	}
+	return scoreJobFromProto(job), nil
+}
+
+// ScoreJob return the progress of the ComputeScores job with given ID.
+func (cli *apiClient) ScoreJob(ctx context.Context, id uuid.UUID) (*ScoreJob, error) {
+	job, err := cli.lgc.GetScoreJob(ctx, &api.ID{Uuid: id[:]})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return scoreJobFromProto(job), nil
+}
 
+// UpsertRedirect implements linkgraph.Graph.
+func (cli *apiClient) UpsertRedirect(ctx context.Context, redirect *linkgraph.Redirect) error {
+	rpcRedirect, err := cli.lgc.UpsertRedirect(ctx, redirectToProto(redirect))
 	if err != nil {
-		return err
+		return fromStatus(err)
 	}
 
-	edge.UpdateAt = rpcEdge.UpdatedAt.AsTime()
+	*redirect = *redirectFromProto(rpcRedirect)
 	return nil
 }
 
-// UpsertLink implements linkgraph.Graph.
-func (cli *apiClient) UpsertLink(link *linkgraph.Link) error {
+// ResolveRedirect implements linkgraph.Graph.
+func (cli *apiClient) ResolveRedirect(ctx context.Context, linkID uuid.UUID) ([]*linkgraph.Redirect, error) {
+	res, err := cli.lgc.ResolveRedirect(ctx, &api.ID{Uuid: linkID[:]})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
 
-	rpcLink, err := cli.lgc.UpsertLink(cli.ctx, &api.Link{
-		Uuid:        link.ID[:],
-		Url:         link.URL,
-		RetrievedAt: timestamppb.New(link.RetrievedAt),
-	})
+	var chain []*linkgraph.Redirect
+	for _, r := range res.Redirects {
+		chain = append(chain, redirectFromProto(r))
+	}
+	return chain, nil
+}
+
+// UpsertLinks implements linkgraph.Graph.
+func (cli *apiClient) UpsertLinks(ctx context.Context, links []*linkgraph.Link) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := cli.lgc.UpsertLinks(ctx)
+	if err != nil {
+		return fromStatus(err)
+	}
+	for _, link := range links {
+		if err := stream.Send(linkToProto(link)); err != nil {
+			// the actual error is reported by CloseAndRecv
+			break
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return fromStatus(err)
+	}
+	for i, rpcLink := range res.Links {
+		if i < len(links) {
+			*links[i] = *linkFromProto(rpcLink)
+		}
+	}
+	return batchErrorFromProto(len(links), res.Errors)
+}
+
+// UpsertEdges implements linkgraph.Graph.
+func (cli *apiClient) UpsertEdges(ctx context.Context, edges []*linkgraph.Edge) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := cli.lgc.UpsertEdges(ctx)
+	if err != nil {
+		return fromStatus(err)
+	}
+	for _, edge := range edges {
+		if err := stream.Send(edgeToProto(edge)); err != nil {
+			// the actual error is reported by CloseAndRecv
+			break
+		}
+	}
 
+	res, err := stream.CloseAndRecv()
 	if err != nil {
-		return err
+		return fromStatus(err)
 	}
-	link.ID = uuid.UUID(rpcLink.Uuid)
-	link.RetrievedAt = rpcLink.RetrievedAt.AsTime()
+	for i, rpcEdge := range res.Edges {
+		if i < len(edges) {
+			*edges[i] = *edgeFromProto(rpcEdge)
+		}
+	}
+	return batchErrorFromProto(len(edges), res.Errors)
+}
+
+// UpsertLink implements linkgraph.Graph.
+func (cli *apiClient) UpsertLink(ctx context.Context, link *linkgraph.Link) error {
+
+	rpcLink, err := cli.lgc.UpsertLink(ctx, linkToProto(link))
+	if err != nil {
+		return fromStatus(err)
+	}
+	*link = *linkFromProto(rpcLink)
 	return nil
 }
 
 //======== link iterator
 
+// the iterators reconnect when the stream fail with Unavailable status and
+// continue right after the last received item, up to maxStreamRetries
+// consecutive attempts.
+const (
+	maxStreamRetries   = 3
+	streamRetryBackoff = 100 * time.Millisecond
+)
+
+// waitRetry sleep before the given retry attempt, it return false if attempt
+// exceed maxStreamRetries or ctx is done while waiting.
+func waitRetry(ctx context.Context, attempt int) bool {
+	if attempt > maxStreamRetries {
+		return false
+	}
+
+	timer := time.NewTimer(streamRetryBackoff << (attempt - 1))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
 var _ linkgraph.LinkIterator = (*linkIterator)(nil)
 
 type linkIterator struct {
+	// reopen the stream from r.ResumeToken which track the last received
+	// link
+	ctx     context.Context
+	reopen  func() (api.LinkGraph_LinksClient, error)
+	r       *api.Range
+	retries int
+
 	stream api.LinkGraph_LinksClient
 
 	// current retreived link
@@ -157,7 +451,8 @@ type linkIterator struct {
 
 // Close implements linkgraph.LinkIterator.
 func (it *linkIterator) Close() error {
-	return it.stream.CloseSend()
+	it.cancelFn()
+	return nil
 }
 
 // Error implements linkgraph.LinkIterator.
@@ -172,23 +467,36 @@ func (it *linkIterator) Link() *linkgraph.Link {
 
 // Next implements linkgraph.LinkIterator.
 func (it *linkIterator) Next() bool {
-	rpcLink, err := it.stream.Recv()
-	if err != nil {
+	for {
+		rpcLink, err := it.stream.Recv()
+		if err == nil {
+			it.link = linkFromProto(rpcLink)
+			it.r.ResumeToken = rpcLink.ResumeToken
+			it.retries = 0
+			return true
+		}
+
+		for status.Code(err) == codes.Unavailable {
+			it.retries++
+			if !waitRetry(it.ctx, it.retries) {
+				break
+			}
+			var stream api.LinkGraph_LinksClient
+			if stream, err = it.reopen(); err == nil {
+				it.stream = stream
+			}
+		}
+		if err == nil {
+			continue
+		}
 
 		//stream will return EOF if no more data to received
 		if err != io.EOF {
-			it.err = err
+			it.err = fromStatus(err)
 		}
 		it.cancelFn()
 		return false
 	}
-	it.link = &linkgraph.Link{
-		ID:          uuidFromBytes(rpcLink.Uuid),
-		URL:         rpcLink.Url,
-		RetrievedAt: rpcLink.RetrievedAt.AsTime(),
-	}
-
-	return true
 }
 
 //======== edge iterator
@@ -196,6 +504,14 @@ func (it *linkIterator) Next() bool {
 var _ linkgraph.EdgeIterator = (*edgeIterator)(nil)
 
 type edgeIterator struct {
+	// reopen the stream from r.ResumeToken which track the last received
+	// edge, nil if the stream can't be resumed
+	ctx     context.Context
+	reopen  func() (api.LinkGraph_EdgesClient, error)
+	r       *api.Range
+	retries int
+
+	// OutEdges and InEdges stream have the same method set
 	stream api.LinkGraph_EdgesClient
 
 	// current retreived link
@@ -211,7 +527,8 @@ type edgeIterator struct {
 
 // Close implements linkgraph.EdgeIterator.
 func (it *edgeIterator) Close() error {
-	return it.stream.CloseSend()
+	it.cancelFn()
+	return nil
 }
 
 // Edge implements linkgraph.EdgeIterator.
@@ -226,32 +543,36 @@ func (it *edgeIterator) Error() error {
 
 // Next implements linkgraph.EdgeIterator.
 func (it *edgeIterator) Next() bool {
-	rpcEdge, err := it.stream.Recv()
-	if err != nil {
+	for {
+		rpcEdge, err := it.stream.Recv()
+		if err == nil {
+			it.edge = edgeFromProto(rpcEdge)
+			if it.r != nil {
+				it.r.ResumeToken = rpcEdge.ResumeToken
+			}
+			it.retries = 0
+			return true
+		}
+
+		for it.reopen != nil && status.Code(err) == codes.Unavailable {
+			it.retries++
+			if !waitRetry(it.ctx, it.retries) {
+				break
+			}
+			var stream api.LinkGraph_EdgesClient
+			if stream, err = it.reopen(); err == nil {
+				it.stream = stream
+			}
+		}
+		if err == nil {
+			continue
+		}
 
 		//stream will return EOF if no more data to received
 		if err != io.EOF {
-			it.err = err
+			it.err = fromStatus(err)
 		}
 		it.cancelFn()
 		return false
 	}
-	it.edge = &linkgraph.Edge{
-		ID:       uuid.UUID(rpcEdge.Uuid),
-		Src:      uuid.UUID(rpcEdge.SrcUuid),
-		Dst:      uuid.UUID(rpcEdge.DstUuid),
-		UpdateAt: rpcEdge.UpdatedAt.AsTime(),
-	}
-
-	return true
-}
-
-func uuidFromBytes(b []byte) uuid.UUID {
-	if len(b) != 16 {
-		return uuid.Nil
-	}
-
-	var dst uuid.UUID
-	copy(dst[:], b)
-	return dst
 }
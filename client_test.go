@@ -0,0 +1,400 @@
+package linkstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/api"
+	"github.com/odit-bit/linkstore/linkgraph"
+	"github.com/odit-bit/linkstore/linkgraph/graphtest"
+	"github.com/odit-bit/linkstore/linkgraph/memory"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func Test_client_suite(t *testing.T) {
+	graphtest.Run(t, func(t *testing.T) linkgraph.Graph {
+		return newTestClient(t, memory.New())
+	})
+}
+
+func Test_client_name_based_ids(t *testing.T) {
+	namespace := uuid.New()
+	graphtest.RunNameBasedIDs(t, namespace, func(t *testing.T) linkgraph.Graph {
+		return newTestClient(t, memory.New(memory.WithNameBasedIDs(namespace)))
+	})
+}
+
+func Test_client_resume(t *testing.T) {
+	t.Run("links stream resume after unavailable", test_resume_links)
+	t.Run("edges stream resume after unavailable", test_resume_edges)
+	t.Run("give up after max retries", test_resume_give_up)
+}
+
+func test_resume_links(t *testing.T) {
+	ctx := context.Background()
+	graph := memory.New()
+	expected := seedGraph(ctx, t, graph, 10)
+
+	// every stream break after 3 links, twice
+	cli := newFlakyClient(t, graph, 3, 2)
+	it, err := cli.Links(ctx, uuid.Nil, maxTestUUID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	var got []uuid.UUID
+	for it.Next() {
+		got = append(got, it.Link().ID)
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(expected.links) {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", got, expected.links)
+	}
+}
+
+func test_resume_edges(t *testing.T) {
+	ctx := context.Background()
+	graph := memory.New()
+	expected := seedGraph(ctx, t, graph, 10)
+
+	cli := newFlakyClient(t, graph, 4, 2)
+	it, err := cli.Edges(ctx, uuid.Nil, maxTestUUID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	var got []uuid.UUID
+	for it.Next() {
+		got = append(got, it.Edge().ID)
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(expected.edges) {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", got, expected.edges)
+	}
+}
+
+func test_resume_give_up(t *testing.T) {
+	ctx := context.Background()
+	graph := memory.New()
+	seedGraph(ctx, t, graph, 10)
+
+	// the stream never deliver anything
+	cli := newFlakyClient(t, graph, 0, maxStreamRetries+1)
+	it, err := cli.Links(ctx, uuid.Nil, maxTestUUID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	if it.Next() {
+		t.Fatal("expected iteration to fail")
+	}
+	if status.Code(it.Error()) != codes.Unavailable {
+		t.Fatalf("\ngot: %v\nexpect: %v", it.Error(), codes.Unavailable)
+	}
+}
+
+func Test_client_url_normalizer(t *testing.T) {
+	ctx := context.Background()
+	normalizer := linkgraph.NewNormalizer(linkgraph.MergeSchemes(), linkgraph.StripTrailingSlash())
+	cli := newTestClientWithServer(t, NewServer(memory.New(), WithURLNormalizer(normalizer)))
+
+	link := &linkgraph.Link{URL: "http://Example.com/a/"}
+	if err := cli.UpsertLink(ctx, link); err != nil {
+		t.Fatal(err)
+	}
+	if link.URL != "https://example.com/a" || link.OriginalURL != "http://Example.com/a/" {
+		t.Fatalf("\ngot: %s %s\nexpect: %s %s", link.URL, link.OriginalURL, "https://example.com/a", "http://Example.com/a/")
+	}
+
+	batch := []*linkgraph.Link{
+		{URL: "https://example.com/a#top"},
+		{URL: "example.com/relative"},
+		{URL: "https://example.com/b"},
+	}
+	err := cli.UpsertLinks(ctx, batch)
+	var batchErr *linkgraph.BatchError
+	if !errors.As(err, &batchErr) || !errors.Is(batchErr.Errs[1], linkgraph.ErrInvalidURL) {
+		t.Fatalf("\ngot: %v\nexpect: %v for the second link", err, linkgraph.ErrInvalidURL)
+	}
+	if batch[0].ID != link.ID || batch[2].ID == uuid.Nil {
+		t.Fatalf("\ngot: %v %v\nexpect: %v", batch[0].ID, batch[2].ID, link.ID)
+	}
+
+	if err := cli.UpsertLink(ctx, &linkgraph.Link{URL: "/relative"}); !errors.Is(err, linkgraph.ErrInvalidURL) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrInvalidURL)
+	}
+
+	other, err := cli.LookupLinkByURL(ctx, "HTTPS://example.com:443/a/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID != link.ID {
+		t.Fatalf("\ngot: %v\nexpect: %v", other.ID, link.ID)
+	}
+
+	resolved, err := cli.ResolveURLs(ctx, []string{"http://example.com/a", "https://example.com/b/", "/relative"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 2 || resolved["http://example.com/a"] != link.ID || resolved["https://example.com/b/"] != batch[2].ID {
+		t.Fatalf("\ngot: %v", resolved)
+	}
+}
+
+func Test_client_score_jobs(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	g := &blockingGraph{Graph: memory.New(), release: release}
+	cli := newTestClient(t, g)
+	seed := seedGraph(ctx, t, g, 5)
+
+	job, err := cli.ComputeScores(ctx, ScoreJobSpec{Kind: linkgraph.ScoreTrustRank, Seeds: seed.links[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State != JobRunning || job.Kind != linkgraph.ScoreTrustRank {
+		t.Fatalf("\ngot: %+v\nexpect: running trustrank job", job)
+	}
+
+	// one job at a time
+	if _, err := cli.ComputeScores(ctx, ScoreJobSpec{Kind: linkgraph.ScorePageRank}); !errors.Is(err, ErrJobRunning) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, ErrJobRunning)
+	}
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.State == JobRunning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if job, err = cli.ScoreJob(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if job.State != JobSucceeded || job.Progress.Phase != "done" || job.Progress.Stored != 5 || job.FinishedAt.IsZero() {
+		t.Fatalf("\ngot: %+v\nexpect: succeeded job with 5 stored", job)
+	}
+
+	// every link is on the cycle from the seed
+	for _, id := range seed.links {
+		link, err := cli.LookupLink(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if link.TrustRank <= 0 || link.PageRank != 0 {
+			t.Fatalf("\ngot: trust %v pagerank %v", link.TrustRank, link.PageRank)
+		}
+	}
+
+	if _, err := cli.ComputeScores(ctx, ScoreJobSpec{Kind: 0}); !errors.Is(err, linkgraph.ErrUnknownScoreKind) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrUnknownScoreKind)
+	}
+	for _, spec := range []ScoreJobSpec{
+		{Kind: linkgraph.ScoreTrustRank},
+		{Kind: linkgraph.ScorePageRank, Seeds: seed.links[:1]},
+		{Kind: linkgraph.ScorePageRank, DampingFactor: 1},
+	} {
+		if _, err := cli.ComputeScores(ctx, spec); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("spec %+v\ngot: %v\nexpect: invalid argument", spec, err)
+		}
+	}
+
+	if _, err := cli.ScoreJob(ctx, uuid.New()); status.Code(err) != codes.NotFound {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, codes.NotFound)
+	}
+}
+
+func Test_client_score_job_close(t *testing.T) {
+	ctx := context.Background()
+	g := &blockingGraph{Graph: memory.New(), release: make(chan struct{})}
+	srv := NewServer(g)
+	cli := newTestClientWithServer(t, srv)
+	seedGraph(ctx, t, g, 5)
+
+	job, err := cli.ComputeScores(ctx, ScoreJobSpec{Kind: linkgraph.ScorePageRank})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the job is cancelled while loading the graph
+	srv.Close()
+	if job, err = cli.ScoreJob(ctx, job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if job.State != JobFailed || job.Err == "" {
+		t.Fatalf("\ngot: %+v\nexpect: failed job", job)
+	}
+
+	if _, err := cli.ComputeScores(ctx, ScoreJobSpec{Kind: linkgraph.ScorePageRank}); status.Code(err) != codes.Canceled {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, codes.Canceled)
+	}
+}
+
+// blockingGraph hold the Links scans until release is closed.
+type blockingGraph struct {
+	linkgraph.Graph
+	release chan struct{}
+}
+
+func (g *blockingGraph) Links(ctx context.Context, fromID, toID uuid.UUID, retrieveBefore time.Time) (linkgraph.LinkIterator, error) {
+	select {
+	case <-g.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return g.Graph.Links(ctx, fromID, toID, retrieveBefore)
+}
+
+var maxTestUUID = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
+
+type seeded struct {
+	links []uuid.UUID
+	edges []uuid.UUID
+}
+
+// seedGraph create n links, each link has edge to the next one. the IDs are
+// returned in iteration order.
+func seedGraph(ctx context.Context, t *testing.T, graph linkgraph.Graph, n int) seeded {
+	t.Helper()
+
+	links := make([]*linkgraph.Link, n)
+	for i := range links {
+		links[i] = &linkgraph.Link{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+	if err := graph.UpsertLinks(ctx, links); err != nil {
+		t.Fatal(err)
+	}
+
+	edges := make([]*linkgraph.Edge, n)
+	for i := range edges {
+		edges[i] = &linkgraph.Edge{Src: links[i].ID, Dst: links[(i+1)%n].ID}
+	}
+	if err := graph.UpsertEdges(ctx, edges); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Slice(links, func(i, j int) bool { return links[i].ID.String() < links[j].ID.String() })
+	sort.Slice(edges, func(i, j int) bool { return edges[i].Src.String() < edges[j].Src.String() })
+
+	var res seeded
+	for i := range links {
+		res.links = append(res.links, links[i].ID)
+		res.edges = append(res.edges, edges[i].ID)
+	}
+	return res
+}
+
+// flakyServer break the first streams with Unavailable status after sending
+// a few items.
+type flakyServer struct {
+	*GraphServer
+
+	mu       sync.Mutex
+	sendOK   int
+	failures int
+}
+
+func (srv *flakyServer) limit() int {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if srv.failures == 0 {
+		return -1
+	}
+	srv.failures--
+	return srv.sendOK
+}
+
+func (srv *flakyServer) Links(r *api.Range, w api.LinkGraph_LinksServer) error {
+	return srv.GraphServer.Links(r, &flakyLinksStream{LinkGraph_LinksServer: w, left: srv.limit()})
+}
+
+func (srv *flakyServer) Edges(r *api.Range, w api.LinkGraph_EdgesServer) error {
+	return srv.GraphServer.Edges(r, &flakyEdgesStream{LinkGraph_EdgesServer: w, left: srv.limit()})
+}
+
+type flakyLinksStream struct {
+	api.LinkGraph_LinksServer
+	left int
+}
+
+func (s *flakyLinksStream) Send(link *api.Link) error {
+	if s.left == 0 {
+		return status.Error(codes.Unavailable, "flaky")
+	}
+	s.left--
+	return s.LinkGraph_LinksServer.Send(link)
+}
+
+type flakyEdgesStream struct {
+	api.LinkGraph_EdgesServer
+	left int
+}
+
+func (s *flakyEdgesStream) Send(edge *api.Edge) error {
+	if s.left == 0 {
+		return status.Error(codes.Unavailable, "flaky")
+	}
+	s.left--
+	return s.LinkGraph_EdgesServer.Send(edge)
+}
+
+// newFlakyClient serve graph through flakyServer, see newTestClient.
+func newFlakyClient(t *testing.T, graph linkgraph.Graph, sendOK, failures int) *apiClient {
+	return newTestClientWithServer(t, &flakyServer{GraphServer: NewServer(graph), sendOK: sendOK, failures: failures})
+}
+
+// newTestClient serve graph over in-process grpc connection and return the
+// client connected to it.
+func newTestClient(t *testing.T, graph linkgraph.Graph) *apiClient {
+	t.Helper()
+	return newTestClientWithServer(t, NewServer(graph))
+}
+
+func newTestClientWithServer(t *testing.T, srv api.LinkGraphServer) *apiClient {
+	t.Helper()
+
+	listen := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	api.RegisterLinkGraphServer(grpcServer, srv)
+	go func() { _ = grpcServer.Serve(listen) }()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listen.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		grpcServer.Stop()
+	})
+
+	cli, err := NewClient(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cli
+}
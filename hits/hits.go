@@ -0,0 +1,314 @@
+// Package hits compute the HITS hub and authority scores of the links of a
+// linkgraph.Graph and store them back as linkgraph.ScoreHub and
+// linkgraph.ScoreAuthority.
+//
+// the scores are computed over the whole graph, or over the focused subgraph
+// of a root set: the root links expanded by one hop through their out and in
+// edges. the subgraph is loaded in memory, then every partition of the links
+// accumulate its scores in its own goroutine:
+//
+//	c := hits.New(graph, hits.WithRoot(ids...))
+//	res, err := c.Run(ctx)
+package hits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/internal/graphsnap"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+const (
+	defaultTolerance     = 1e-6
+	defaultMaxIterations = 100
+	defaultBatchSize     = 1000
+	defaultInLinkLimit   = 50
+)
+
+// ErrNoRoot is returned when none of the root links is part of the graph.
+var ErrNoRoot = errors.New("no root link in the graph")
+
+// Calculator compute the HITS scores of a graph, it can be run any number of
+// time.
+type Calculator struct {
+	g linkgraph.Graph
+
+	root          []uuid.UUID
+	inLinkLimit   int
+	tolerance     float64
+	maxIterations int
+	partitions    int
+	batchSize     int
+	edgeOpts      []linkgraph.EdgeOption
+}
+
+// Option configure Calculator.
+type Option func(*Calculator)
+
+// WithRoot compute the scores of the focused subgraph of the root links
+// instead of the whole graph: the root, the links they link to and the links
+// that link to them. unknown root is ignored.
+func WithRoot(ids ...uuid.UUID) Option {
+	return func(c *Calculator) {
+		c.root = ids
+	}
+}
+
+// WithInLinkLimit bound the number of links added to the focused subgraph
+// for the in edges of every root link, so a popular root doesn't pull most
+// of the graph. it default to 50, zero for no limit.
+func WithInLinkLimit(n int) Option {
+	return func(c *Calculator) {
+		c.inLinkLimit = n
+	}
+}
+
+// WithTolerance set the convergence threshold, the iteration stop once the
+// hub and authority scores change by less than tolerance in total (L1 norm).
+// it default to 1e-6.
+func WithTolerance(tolerance float64) Option {
+	return func(c *Calculator) {
+		c.tolerance = tolerance
+	}
+}
+
+// WithMaxIterations bound the number of iterations when the scores don't
+// converge, it default to 100.
+func WithMaxIterations(n int) Option {
+	return func(c *Calculator) {
+		c.maxIterations = n
+	}
+}
+
+// WithPartitions set the number of uuid partitions loaded and updated in
+// parallel, it default to the number of CPU.
+func WithPartitions(n int) Option {
+	return func(c *Calculator) {
+		c.partitions = n
+	}
+}
+
+// WithBatchSize set the number of scores stored per UpdateScores call, it
+// default to 1000.
+func WithBatchSize(n int) Option {
+	return func(c *Calculator) {
+		c.batchSize = n
+	}
+}
+
+// WithEdgeOptions replace the options of the edges, by default nofollow
+// edges are skipped and redirects are resolved (linkgraph.SkipNofollow and
+// linkgraph.ResolveRedirects). the focused subgraph never resolve redirects.
+func WithEdgeOptions(opts ...linkgraph.EdgeOption) Option {
+	return func(c *Calculator) {
+		c.edgeOpts = opts
+	}
+}
+
+// New return calculator for the links of g.
+func New(g linkgraph.Graph, opts ...Option) *Calculator {
+	c := Calculator{
+		g:             g,
+		inLinkLimit:   defaultInLinkLimit,
+		tolerance:     defaultTolerance,
+		maxIterations: defaultMaxIterations,
+		partitions:    runtime.NumCPU(),
+		batchSize:     defaultBatchSize,
+		edgeOpts:      []linkgraph.EdgeOption{linkgraph.SkipNofollow(), linkgraph.ResolveRedirects()},
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// Result of a computation.
+type Result struct {
+	// scores of every link of the (sub)graph, the squares of each kind sum
+	// to 1 unless there is no edge
+	Hubs        map[uuid.UUID]float64
+	Authorities map[uuid.UUID]float64
+
+	// number of links and (distinct) edges of the (sub)graph
+	Links int
+	Edges int
+
+	// number of iterations run and whether the scores converged before
+	// reaching the maximum
+	Iterations int
+	Converged  bool
+
+	// change of the scores (L1 norm) in the last iteration
+	Delta float64
+}
+
+// Run compute the scores and store them in the graph. only the links of the
+// (sub)graph are updated, the scores of the other links are left untouched.
+func (c *Calculator) Run(ctx context.Context) (*Result, error) {
+	if c.batchSize < 1 {
+		return nil, fmt.Errorf("hits: invalid batch size %d", c.batchSize)
+	}
+
+	res, s, err := c.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.store(ctx, s, res); err != nil {
+		return nil, fmt.Errorf("hits: store scores: %v", err)
+	}
+	return res, nil
+}
+
+// Compute compute the scores without storing them.
+func (c *Calculator) Compute(ctx context.Context) (*Result, error) {
+	res, _, err := c.compute(ctx)
+	return res, err
+}
+
+func (c *Calculator) compute(ctx context.Context) (*Result, *snapshot, error) {
+	if c.inLinkLimit < 0 {
+		return nil, nil, fmt.Errorf("hits: invalid in link limit %d", c.inLinkLimit)
+	}
+
+	ranges, err := linkgraph.Partition(c.partitions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hits: %v", err)
+	}
+
+	var s *snapshot
+	if c.root != nil {
+		s, err = loadFocused(ctx, c.g, c.root, ranges, c.inLinkLimit, c.edgeOpts)
+		if err == nil && s.Len() == 0 {
+			err = ErrNoRoot
+		}
+	} else {
+		var loaded *graphsnap.Snapshot
+		if loaded, err = graphsnap.Load(ctx, c.g, ranges, c.edgeOpts); err == nil {
+			s = withOut(loaded)
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("hits: load graph: %w", err)
+	}
+
+	res := Result{Links: s.Len(), Edges: s.Edges()}
+	hubs, authorities, err := c.iterate(ctx, s, &res)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hits: %w", err)
+	}
+
+	res.Hubs = make(map[uuid.UUID]float64, s.Len())
+	res.Authorities = make(map[uuid.UUID]float64, s.Len())
+	for v, id := range s.IDs {
+		res.Hubs[id] = hubs[v]
+		res.Authorities[id] = authorities[v]
+	}
+	return &res, s, nil
+}
+
+// iterate alternate the authority update (sum of the hub scores of the in
+// edges) and the hub update (sum of the authority scores of the out edges),
+// each followed by the L2 normalization of the scores.
+func (c *Calculator) iterate(ctx context.Context, s *snapshot, res *Result) (hubs, authorities []float64, err error) {
+	n := s.Len()
+	if n == 0 {
+		res.Converged = true
+		return nil, nil, nil
+	}
+
+	var (
+		hub     = make([]float64, n)
+		auth    = make([]float64, n)
+		nextHub = make([]float64, n)
+		next    = make([]float64, n)
+
+		// per partition sums, reduced after every pass
+		squares = make([]float64, s.Partitions())
+		delta   = make([]float64, s.Partitions())
+	)
+	for v := range hub {
+		hub[v] = 1 / math.Sqrt(float64(n))
+		auth[v] = hub[v]
+	}
+
+	// update compute next from the scores of the neighbours of every link,
+	// normalize it and add its change from prev to delta
+	update := func(next, prev, from []float64, start, neighbours []int) {
+		s.Parallel(func(p, lo, hi int) {
+			squares[p] = 0
+			for v := lo; v < hi; v++ {
+				var score float64
+				for _, u := range neighbours[start[v]:start[v+1]] {
+					score += from[u]
+				}
+				next[v] = score
+				squares[p] += score * score
+			}
+		})
+
+		norm := math.Sqrt(graphsnap.Sum(squares))
+		s.Parallel(func(p, lo, hi int) {
+			for v := lo; v < hi; v++ {
+				if norm > 0 {
+					next[v] /= norm
+				}
+				delta[p] += math.Abs(next[v] - prev[v])
+			}
+		})
+	}
+
+	for res.Iterations < c.maxIterations {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		for p := range delta {
+			delta[p] = 0
+		}
+		update(next, auth, hub, s.InStart, s.In)
+		update(nextHub, hub, next, s.outStart, s.out)
+
+		auth, next = next, auth
+		hub, nextHub = nextHub, hub
+		res.Iterations++
+		res.Delta = graphsnap.Sum(delta)
+		if res.Delta < c.tolerance {
+			res.Converged = true
+			break
+		}
+	}
+
+	return hub, auth, nil
+}
+
+// store write the scores of every partition in batches, the partitions are
+// stored concurrently.
+func (c *Calculator) store(ctx context.Context, s *snapshot, res *Result) error {
+	errs := make([]error, s.Partitions())
+	s.Parallel(func(p, from, to int) {
+		for lo := from; lo < to && errs[p] == nil; lo += c.batchSize {
+			hi := lo + c.batchSize
+			if hi > to {
+				hi = to
+			}
+
+			hubs := make(map[uuid.UUID]float64, hi-lo)
+			authorities := make(map[uuid.UUID]float64, hi-lo)
+			for _, id := range s.IDs[lo:hi] {
+				hubs[id] = res.Hubs[id]
+				authorities[id] = res.Authorities[id]
+			}
+
+			if errs[p] = c.g.UpdateScores(ctx, linkgraph.ScoreHub, hubs); errs[p] == nil {
+				errs[p] = c.g.UpdateScores(ctx, linkgraph.ScoreAuthority, authorities)
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
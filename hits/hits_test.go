@@ -0,0 +1,258 @@
+package hits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/internal/graphsnap"
+	"github.com/odit-bit/linkstore/linkgraph"
+	"github.com/odit-bit/linkstore/linkgraph/memory"
+)
+
+func Test_hits(t *testing.T) {
+	t.Run("hubs and authorities", test_hits_bipartite)
+	t.Run("partitions agree", test_hits_partitions)
+	t.Run("focused subgraph", test_hits_focused)
+	t.Run("in link limit", test_hits_in_link_limit)
+	t.Run("store scores", test_hits_store)
+	t.Run("invalid config", test_hits_invalid)
+}
+
+func test_hits_bipartite(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+
+	// 0,1,2 are hubs of the authorities 3,4, only 0 link to 5
+	edges := [][2]int{{0, 3}, {0, 4}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {0, 5}}
+	ids := seedGraph(ctx, t, g, 6, edges)
+
+	res, err := New(g, WithTolerance(1e-12)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Converged || res.Links != 6 || res.Edges != 7 {
+		t.Fatalf("\ngot: %+v", res)
+	}
+
+	hubs, authorities := referenceHITS(6, edges)
+	for i, id := range ids {
+		assertScore(t, res.Hubs[id], hubs[i])
+		assertScore(t, res.Authorities[id], authorities[i])
+	}
+	if res.Hubs[ids[0]] <= res.Hubs[ids[1]] || res.Authorities[ids[3]] <= res.Authorities[ids[5]] || res.Hubs[ids[3]] != 0 {
+		t.Fatalf("\ngot: hubs %v\nauthorities %v", res.Hubs, res.Authorities)
+	}
+}
+
+func test_hits_partitions(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+
+	rnd := rand.New(rand.NewSource(1))
+	var edges [][2]int
+	for i := 0; i < 1000; i++ {
+		edges = append(edges, [2]int{rnd.Intn(200), rnd.Intn(200)})
+	}
+	ids := seedGraph(ctx, t, g, 200, edges)
+
+	single, err := New(g, WithPartitions(1), WithTolerance(1e-12)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := New(g, WithPartitions(7), WithTolerance(1e-12)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if single.Iterations != parallel.Iterations || single.Edges != parallel.Edges {
+		t.Fatalf("\ngot: %+v\nexpect: %+v", parallel, single)
+	}
+	for _, id := range ids {
+		assertScore(t, parallel.Hubs[id], single.Hubs[id])
+		assertScore(t, parallel.Authorities[id], single.Authorities[id])
+	}
+}
+
+func test_hits_focused(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+
+	// root 0 link to 1 and is linked from 2, 3 is two hops away and 4 is
+	// not connected. the edge 1 -> 2 is between links of the base set
+	ids := seedGraph(ctx, t, g, 5, [][2]int{{0, 1}, {2, 0}, {1, 2}, {3, 2}, {1, 3}, {4, 4}})
+
+	res, err := New(g, WithRoot(ids[0], uuid.New()), WithTolerance(1e-12), WithPartitions(3)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Links != 3 || res.Edges != 3 {
+		t.Fatalf("\ngot: %d links %d edges\nexpect: 3 links 3 edges", res.Links, res.Edges)
+	}
+
+	// the base set is a cycle
+	for _, id := range ids[:3] {
+		assertScore(t, res.Hubs[id], 1/math.Sqrt(3))
+		assertScore(t, res.Authorities[id], 1/math.Sqrt(3))
+	}
+	for _, id := range ids[3:] {
+		if _, ok := res.Hubs[id]; ok {
+			t.Fatalf("%v is outside the base set", id)
+		}
+	}
+
+	if _, err := New(g, WithRoot(uuid.New())).Compute(ctx); !errors.Is(err, ErrNoRoot) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, ErrNoRoot)
+	}
+}
+
+func test_hits_in_link_limit(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+
+	// 1..5 link to the root 0, nofollow edges are skipped
+	ids := seedGraph(ctx, t, g, 7, [][2]int{{1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}})
+	if err := g.UpsertEdge(ctx, &linkgraph.Edge{Src: ids[6], Dst: ids[0], Rel: linkgraph.RelNofollow}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := New(g, WithRoot(ids[0]), WithInLinkLimit(3)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Links != 4 || res.Edges != 3 {
+		t.Fatalf("\ngot: %d links %d edges\nexpect: 4 links 3 edges", res.Links, res.Edges)
+	}
+
+	res, err = New(g, WithRoot(ids[0]), WithInLinkLimit(0)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Links != 6 || res.Edges != 5 {
+		t.Fatalf("\ngot: %d links %d edges\nexpect: 6 links 5 edges", res.Links, res.Edges)
+	}
+}
+
+func test_hits_store(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+	ids := seedGraph(ctx, t, g, 20, [][2]int{{0, 1}, {1, 2}, {2, 0}, {3, 1}, {3, 2}})
+	if err := g.UpdateScores(ctx, linkgraph.ScorePageRank, map[uuid.UUID]float64{ids[0]: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := New(g, WithBatchSize(3), WithPartitions(4)).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the scores are streamed with the links
+	it, err := g.Links(ctx, uuid.Nil, linkgraph.MaxUUID, graphsnap.Everything)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	n := 0
+	for ; it.Next(); n++ {
+		link := it.Link()
+		if link.HubScore != res.Hubs[link.ID] || link.AuthorityScore != res.Authorities[link.ID] {
+			t.Fatalf("\ngot: hub %v authority %v\nexpect: hub %v authority %v", link.HubScore, link.AuthorityScore, res.Hubs[link.ID], res.Authorities[link.ID])
+		}
+		if link.ID == ids[0] && link.PageRank != 0.5 {
+			t.Fatalf("\ngot: %v\nexpect: %v", link.PageRank, 0.5)
+		}
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 20 || res.Hubs[ids[3]] == 0 {
+		t.Fatalf("\ngot: %d links %+v", n, res)
+	}
+}
+
+func test_hits_invalid(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+
+	for _, opt := range []Option{WithPartitions(0), WithBatchSize(0), WithInLinkLimit(-1)} {
+		if _, err := New(g, opt).Run(ctx); err == nil {
+			t.Fatal("expect error")
+		}
+	}
+
+	// empty graph has nothing to score
+	res, err := New(g).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Converged || len(res.Hubs) != 0 {
+		t.Fatalf("\ngot: %+v", res)
+	}
+}
+
+// seedGraph create n links and the edges between them, it return the link IDs
+// in creation order.
+func seedGraph(ctx context.Context, t *testing.T, g linkgraph.Graph, n int, edges [][2]int) []uuid.UUID {
+	t.Helper()
+
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		link := &linkgraph.Link{URL: fmt.Sprintf("https://example.com/%d", i)}
+		if err := g.UpsertLink(ctx, link); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = link.ID
+	}
+	for _, e := range edges {
+		if err := g.UpsertEdge(ctx, &linkgraph.Edge{Src: ids[e[0]], Dst: ids[e[1]]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ids
+}
+
+// referenceHITS is the textbook sequential iteration over distinct edges
+// without self link.
+func referenceHITS(n int, edges [][2]int) (hubs, authorities []float64) {
+	hubs = make([]float64, n)
+	for i := range hubs {
+		hubs[i] = 1
+	}
+
+	normalize := func(scores []float64) {
+		var norm float64
+		for _, s := range scores {
+			norm += s * s
+		}
+		for i := range scores {
+			scores[i] /= math.Sqrt(norm)
+		}
+	}
+
+	for iter := 0; iter < 1000; iter++ {
+		authorities = make([]float64, n)
+		for _, e := range edges {
+			authorities[e[1]] += hubs[e[0]]
+		}
+		normalize(authorities)
+
+		hubs = make([]float64, n)
+		for _, e := range edges {
+			hubs[e[0]] += authorities[e[1]]
+		}
+		normalize(hubs)
+	}
+	return hubs, authorities
+}
+
+func assertScore(t *testing.T, got, expect float64) {
+	t.Helper()
+	if math.Abs(got-expect) > 1e-9 {
+		t.Fatalf("\ngot: %v\nexpect: %v", got, expect)
+	}
+}
@@ -0,0 +1,194 @@
+package hits
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/internal/graphsnap"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// snapshot add the out edges to the graph loaded in memory, HITS follow the
+// edges in both directions.
+type snapshot struct {
+	*graphsnap.Snapshot
+
+	// destinations of the edges that start at link v are
+	// out[outStart[v]:outStart[v+1]]
+	outStart []int
+	out      []int
+}
+
+// withOut build the out edges of s from its in edges.
+func withOut(s *graphsnap.Snapshot) *snapshot {
+	n := s.Len()
+	outStart := make([]int, n+1)
+	for v := 0; v < n; v++ {
+		outStart[v+1] = outStart[v] + s.OutDegree[v]
+	}
+
+	out := make([]int, s.Edges())
+	next := append([]int(nil), outStart[:n]...)
+	for v := 0; v < n; v++ {
+		for _, u := range s.In[s.InStart[v]:s.InStart[v+1]] {
+			out[next[u]] = v
+			next[u]++
+		}
+	}
+	return &snapshot{Snapshot: s, outStart: outStart, out: out}
+}
+
+// loadFocused load the base set of root: the root links, the links they
+// link to and at most inLimit links that link to each of them (zero for no
+// limit), with the edges between them. the edges are read with OutEdges and
+// InEdges, edgeOpts filter them but redirects are not resolved. unknown root
+// is ignored.
+func loadFocused(ctx context.Context, g linkgraph.Graph, root []uuid.UUID, ranges []linkgraph.Range, inLimit int, edgeOpts []linkgraph.EdgeOption) (*snapshot, error) {
+	opts := linkgraph.NewEdgeOptions(edgeOpts...)
+	workers := len(ranges)
+
+	// root set expanded by one hop in both directions
+	found := make([]map[uuid.UUID]bool, workers)
+	err := forEach(ctx, root, workers, func(w int, id uuid.UUID) error {
+		if _, err := g.LookupLink(ctx, id); err != nil {
+			if errors.Is(err, linkgraph.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if found[w] == nil {
+			found[w] = make(map[uuid.UUID]bool)
+		}
+		found[w][id] = true
+
+		err := eachEdge(ctx, g.OutEdges, id, func(edge *linkgraph.Edge) bool {
+			if opts.Match(edge, graphsnap.Everything) {
+				found[w][edge.Dst] = true
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		// sources are ordered, the first inLimit distinct ones are kept
+		in, last := 0, uuid.Nil
+		return eachEdge(ctx, g.InEdges, id, func(edge *linkgraph.Edge) bool {
+			if opts.Match(edge, graphsnap.Everything) && (in == 0 || edge.Src != last) {
+				found[w][edge.Src] = true
+				in, last = in+1, edge.Src
+			}
+			return inLimit == 0 || in < inLimit
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the base set sorted and split like the partitions
+	index := make(map[uuid.UUID]int)
+	var ids []uuid.UUID
+	for _, set := range found {
+		for id := range set {
+			if _, ok := index[id]; !ok {
+				index[id] = 0
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+	for v, id := range ids {
+		index[id] = v
+	}
+
+	bounds := make([]int, len(ranges)+1)
+	for p, r := range ranges {
+		bounds[p] = sort.Search(len(ids), func(i int) bool { return bytes.Compare(ids[i][:], r.From[:]) >= 0 })
+	}
+	bounds[len(ranges)] = len(ids)
+
+	// every edge between links of the base set
+	edges := make([][]graphsnap.Edge, workers)
+	err = forEach(ctx, ids, workers, func(w int, id uuid.UUID) error {
+		src := index[id]
+		seen := make(map[int]bool)
+		return eachEdge(ctx, g.OutEdges, id, func(edge *linkgraph.Edge) bool {
+			dst, ok := index[edge.Dst]
+			if ok && dst != src && !seen[dst] && opts.Match(edge, graphsnap.Everything) {
+				seen[dst] = true
+				edges[w] = append(edges[w], graphsnap.Edge{Src: src, Dst: dst})
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withOut(graphsnap.New(ids, bounds, edges)), nil
+}
+
+// forEach call fn for every id with at most workers goroutines, w is the
+// goroutine of the call. the first error stop the remaining calls.
+func forEach(ctx context.Context, ids []uuid.UUID, workers int, fn func(w int, id uuid.UUID) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	idC := make(chan uuid.UUID)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for id := range idC {
+				if errs[w] != nil {
+					continue
+				}
+				if errs[w] = fn(w, id); errs[w] != nil {
+					cancel()
+				}
+			}
+		}(w)
+	}
+
+feed:
+	for _, id := range ids {
+		select {
+		case idC <- id:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(idC)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// eachEdge call fn for the edges of the iterator returned by edges for id
+// until fn return false.
+func eachEdge(ctx context.Context, edges func(context.Context, uuid.UUID) (linkgraph.EdgeIterator, error), id uuid.UUID, fn func(*linkgraph.Edge) bool) error {
+	it, err := edges(ctx, id)
+	if err != nil {
+		return err
+	}
+	for it.Next() {
+		if !fn(it.Edge()) {
+			break
+		}
+	}
+	if err := it.Error(); err != nil {
+		_ = it.Close()
+		return err
+	}
+	return it.Close()
+}
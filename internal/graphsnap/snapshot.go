@@ -0,0 +1,178 @@
+// Package graphsnap load a linkgraph.Graph in memory for the ranking
+// packages, the links are split into the uuid partitions so every partition
+// can be updated by its own goroutine.
+package graphsnap
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// Everything is the iterator filter that include every link and edge.
+var Everything = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// Snapshot is the graph loaded in memory, links are identified by their
+// position in IDs which is sorted like the partitions.
+type Snapshot struct {
+	IDs   []uuid.UUID
+	Index map[uuid.UUID]int
+
+	// number of distinct destination of every link
+	OutDegree []int
+
+	// sources of the edges that end at link v are In[InStart[v]:InStart[v+1]]
+	InStart []int
+	In      []int
+
+	// links of partition p are [Bounds[p], Bounds[p+1])
+	Bounds []int
+}
+
+// Edge between the links Src and Dst of a snapshot.
+type Edge struct{ Src, Dst int }
+
+// New return the snapshot of the sorted ids split by bounds, edges may be
+// grouped in any way but must be distinct and without link to itself.
+func New(ids []uuid.UUID, bounds []int, edges [][]Edge) *Snapshot {
+	s := Snapshot{IDs: ids, Bounds: bounds}
+	s.Index = make(map[uuid.UUID]int, len(ids))
+	for v, id := range ids {
+		s.Index[id] = v
+	}
+
+	// in-edges are stored as compressed rows
+	n := s.Len()
+	s.OutDegree = make([]int, n)
+	s.InStart = make([]int, n+1)
+	for _, es := range edges {
+		for _, e := range es {
+			s.OutDegree[e.Src]++
+			s.InStart[e.Dst+1]++
+		}
+	}
+	for v := 0; v < n; v++ {
+		s.InStart[v+1] += s.InStart[v]
+	}
+
+	s.In = make([]int, s.InStart[n])
+	next := append([]int(nil), s.InStart[:n]...)
+	for _, es := range edges {
+		for _, e := range es {
+			s.In[next[e.Dst]] = e.Src
+			next[e.Dst]++
+		}
+	}
+	return &s
+}
+
+// Load scan links and edges of g, one range at a time per goroutine. edge to
+// unknown link and link to itself are dropped, multiple edges from the same
+// source to the same destination count once.
+func Load(ctx context.Context, g linkgraph.Graph, ranges []linkgraph.Range, edgeOpts []linkgraph.EdgeOption) (*Snapshot, error) {
+	position := make(map[linkgraph.Range]int, len(ranges))
+	for i, r := range ranges {
+		position[r] = i
+	}
+
+	// links of every range are appended in order, the concatenation is
+	// sorted
+	linkIDs := make([][]uuid.UUID, len(ranges))
+	err := linkgraph.ScanLinks(ctx, g, ranges, len(ranges), Everything, func(r linkgraph.Range, link *linkgraph.Link) error {
+		p := position[r]
+		linkIDs[p] = append(linkIDs[p], link.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	bounds := make([]int, len(ranges)+1)
+	for p, rangeIDs := range linkIDs {
+		ids = append(ids, rangeIDs...)
+		bounds[p+1] = len(ids)
+	}
+	index := make(map[uuid.UUID]int, len(ids))
+	for v, id := range ids {
+		index[id] = v
+	}
+
+	// edges are grouped by source within a range, so duplicates are found
+	// by remembering the destinations of the current source only
+	edges := make([][]Edge, len(ranges))
+	lastSrc := make([]int, len(ranges))
+	seen := make([]map[int]bool, len(ranges))
+	for p := range ranges {
+		lastSrc[p], seen[p] = -1, make(map[int]bool)
+	}
+
+	err = linkgraph.ScanEdges(ctx, g, ranges, len(ranges), Everything, func(r linkgraph.Range, edge *linkgraph.Edge) error {
+		src, srcOK := index[edge.Src]
+		dst, dstOK := index[edge.Dst]
+		if !srcOK || !dstOK || src == dst {
+			return nil
+		}
+
+		p := position[r]
+		if src != lastSrc[p] {
+			lastSrc[p] = src
+			for d := range seen[p] {
+				delete(seen[p], d)
+			}
+		}
+		if seen[p][dst] {
+			return nil
+		}
+		seen[p][dst] = true
+
+		edges[p] = append(edges[p], Edge{Src: src, Dst: dst})
+		return nil
+	}, edgeOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(ids, bounds, edges), nil
+}
+
+// Len return the number of links.
+func (s *Snapshot) Len() int {
+	return len(s.IDs)
+}
+
+// Edges return the number of edges.
+func (s *Snapshot) Edges() int {
+	return len(s.In)
+}
+
+// Partitions return the number of partitions.
+func (s *Snapshot) Partitions() int {
+	return len(s.Bounds) - 1
+}
+
+// Parallel call fn for the links of every partition, each in its own
+// goroutine.
+func (s *Snapshot) Parallel(fn func(p, from, to int)) {
+	var wg sync.WaitGroup
+	for p := 0; p < s.Partitions(); p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			fn(p, s.Bounds[p], s.Bounds[p+1])
+		}(p)
+	}
+	wg.Wait()
+}
+
+// Sum return the sum of values, e.g. of per partition sums.
+func Sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
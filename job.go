@@ -0,0 +1,190 @@
+package linkstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/linkgraph"
+	"github.com/odit-bit/linkstore/pagerank"
+)
+
+// ErrJobRunning is returned by ComputeScores while another job is running.
+var ErrJobRunning = errors.New("score job already running")
+
+// errInvalidJobSpec is wrapped by the errors of ScoreJobSpec that the client
+// must fix.
+var errInvalidJobSpec = errors.New("invalid score job")
+
+// JobState is the state of a ComputeScores job.
+type JobState int
+
+const (
+	JobRunning JobState = iota + 1
+	JobSucceeded
+	JobFailed
+)
+
+// ScoreJobSpec describe the scores computed by a ComputeScores job, zero
+// parameters use the default of the pagerank package.
+type ScoreJobSpec struct {
+	// linkgraph.ScorePageRank compute the global PageRank and take no seed,
+	// linkgraph.ScoreTrustRank compute the PageRank personalized to the
+	// trusted Seeds.
+	Kind  linkgraph.ScoreKind
+	Seeds []uuid.UUID
+
+	DampingFactor float64
+	Tolerance     float64
+	MaxIterations int
+}
+
+// calculator return the calculator of spec over g.
+func (spec ScoreJobSpec) calculator(g linkgraph.Graph, opts ...pagerank.Option) (*pagerank.Calculator, error) {
+	if spec.DampingFactor < 0 || spec.DampingFactor >= 1 {
+		return nil, fmt.Errorf("%w: damping factor %v is not in (0, 1)", errInvalidJobSpec, spec.DampingFactor)
+	}
+	if spec.DampingFactor > 0 {
+		opts = append(opts, pagerank.WithDampingFactor(spec.DampingFactor))
+	}
+	if spec.Tolerance > 0 {
+		opts = append(opts, pagerank.WithTolerance(spec.Tolerance))
+	}
+	if spec.MaxIterations > 0 {
+		opts = append(opts, pagerank.WithMaxIterations(spec.MaxIterations))
+	}
+
+	switch spec.Kind {
+	case linkgraph.ScorePageRank:
+		if len(spec.Seeds) > 0 {
+			return nil, fmt.Errorf("%w: %s take no seed", errInvalidJobSpec, spec.Kind)
+		}
+		return pagerank.New(g, opts...), nil
+	case linkgraph.ScoreTrustRank:
+		if len(spec.Seeds) == 0 {
+			return nil, fmt.Errorf("%w: %s need trusted seeds", errInvalidJobSpec, spec.Kind)
+		}
+		return pagerank.TrustRank(g, spec.Seeds, opts...), nil
+	}
+	return nil, linkgraph.ErrUnknownScoreKind
+}
+
+// ScoreJob report the progress of a ComputeScores job.
+type ScoreJob struct {
+	ID       uuid.UUID
+	Kind     linkgraph.ScoreKind
+	State    JobState
+	Progress pagerank.Progress
+
+	// error of failed job
+	Err string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// maxFinishedJobs is the number of finished jobs remembered by the server.
+const maxFinishedJobs = 100
+
+// scoreJobs run the ComputeScores jobs of a server one at a time, the
+// finished jobs are kept until maxFinishedJobs newer jobs finish.
+type scoreJobs struct {
+	mu       sync.Mutex
+	jobs     map[uuid.UUID]*ScoreJob
+	finished []uuid.UUID
+	running  bool
+
+	// jobs run on ctx, it is cancelled when the server is closed
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newScoreJobs() *scoreJobs {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &scoreJobs{jobs: make(map[uuid.UUID]*ScoreJob), ctx: ctx, cancel: cancel}
+}
+
+// start run the job of spec over g in the background, it outlive the
+// request that started it but not the server.
+func (j *scoreJobs) start(g linkgraph.Graph, spec ScoreJobSpec) (*ScoreJob, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := j.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if j.running {
+		return nil, ErrJobRunning
+	}
+
+	job := &ScoreJob{
+		ID:        uuid.New(),
+		Kind:      spec.Kind,
+		State:     JobRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	c, err := spec.calculator(g, pagerank.WithProgress(func(p pagerank.Progress) {
+		j.mu.Lock()
+		job.Progress = p
+		j.mu.Unlock()
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	j.jobs[job.ID] = job
+	j.running = true
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		_, err := c.Run(j.ctx)
+		j.finish(job, err)
+	}()
+
+	jCopy := *job
+	return &jCopy, nil
+}
+
+func (j *scoreJobs) finish(job *ScoreJob, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job.State, job.FinishedAt = JobSucceeded, time.Now().UTC()
+	if err != nil {
+		job.State, job.Err = JobFailed, err.Error()
+	}
+	j.running = false
+
+	j.finished = append(j.finished, job.ID)
+	if len(j.finished) > maxFinishedJobs {
+		delete(j.jobs, j.finished[0])
+		j.finished = j.finished[1:]
+	}
+}
+
+// stop cancel the running job and wait for it to finish, no job can be
+// started after.
+func (j *scoreJobs) stop() {
+	j.mu.Lock()
+	j.cancel()
+	j.mu.Unlock()
+
+	j.wg.Wait()
+}
+
+// get return a copy of the job with given ID.
+func (j *scoreJobs) get(id uuid.UUID) (*ScoreJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job, ok := j.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	jCopy := *job
+	return &jCopy, true
+}
@@ -4,3 +4,52 @@ import "fmt"
 
 var ErrNotFound = fmt.Errorf("not found")
 var ErrUnknownEdgeLinks = fmt.Errorf("unknown edges's link src or dst")
+
+// ErrInvalidURL is returned for url that can't be normalized.
+var ErrInvalidURL = fmt.Errorf("invalid url")
+
+// ErrLinkIDMismatch is returned by graph with name-based IDs for link whose
+// ID is set but is not the ID of its url.
+var ErrLinkIDMismatch = fmt.Errorf("link id does not match url")
+
+// ErrRedirectLoop is returned for chain of redirects that come back to a link
+// it already passed, including link that redirect to itself.
+var ErrRedirectLoop = fmt.Errorf("redirect loop")
+
+// ErrUnknownScoreKind is returned by UpdateScores for ScoreKind that is not
+// valid.
+var ErrUnknownScoreKind = fmt.Errorf("unknown score kind")
+
+// BatchError report the items of a batch operation that failed, the other
+// items of the batch are still applied.
+type BatchError struct {
+	// Errs has the same length and order as the batch input, the error of
+	// item that succeed is nil.
+	Errs []error
+}
+
+// NewBatchError return *BatchError for errs, or nil if every item succeed.
+func NewBatchError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return &BatchError{Errs: errs}
+		}
+	}
+	return nil
+}
+
+func (e *BatchError) Error() string {
+	var (
+		failed int
+		first  error
+	)
+	for _, err := range e.Errs {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	return fmt.Sprintf("batch: %d of %d item failed, first error: %v", failed, len(e.Errs), first)
+}
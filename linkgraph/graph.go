@@ -1,6 +1,7 @@
 package linkgraph
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,32 +13,127 @@ import (
 2. lookup link by its ID
 3. iterate all link presented in graph
 4. insert or updated edge into graph
-5. iterate the list edges in graph. it REQUIRED By PageRank calculator
+5. iterate the list edges in graph. it REQUIRED By PageRank calculator, see
+   the pagerank package
 6. Delete link that not updated bye crawler
 */
 // because Graph is an abstract data type , so it make sense to defined upfront,
-
+//
+// every operation take a context, cancelling it (or reaching its deadline)
+// abort the underlying query. iterator returned by Links and Edges stay bound
+// to the context they were created with.
 type Graph interface {
 	//
-	UpsertLink(link *Link) error
+	UpsertLink(ctx context.Context, link *Link) error
 
-	//
-	// LookupLink(id uuid.UUID) (*Link, error)
+	// UpsertLinks insert or update a batch of links, every link is updated in
+	// place like UpsertLink. links that share the same url end up with the
+	// same ID. the ID supplied by caller is ignored, unless the graph use
+	// name-based IDs where it must be either zero or the ID of the url, see
+	// NameBasedID.
+	UpsertLinks(ctx context.Context, links []*Link) error
+
+	// LookupLink return the link with given ID, or ErrNotFound if there is
+	// no such link.
+	LookupLink(ctx context.Context, id uuid.UUID) (*Link, error)
+
+	// LookupLinkByURL return the link with given url, or ErrNotFound if there
+	// is no such link. unlike UpsertLink it never modify the graph.
+	LookupLinkByURL(ctx context.Context, url string) (*Link, error)
+
+	// ResolveURLs return the ID of every known link in urls, urls that are not
+	// part of the graph are omitted from the result.
+	ResolveURLs(ctx context.Context, urls []string) (map[string]uuid.UUID, error)
 
-	//return link iterator to iterate link in graph
-	Links(fromID, toID uuid.UUID, retrieveBefore time.Time) (LinkIterator, error)
+	//return link iterator to iterate link in [fromID, toID), links are ordered
+	// by ID. range that end at MaxUUID include it, see Range.
+	//
+	// with name-based IDs the placeholder links are returned too, they have
+	// an empty URL and zero RetrievedAt until the link itself is upserted.
+	Links(ctx context.Context, fromID, toID uuid.UUID, retrieveBefore time.Time) (LinkIterator, error)
 
 	// insert the new edge, the updated scenario will occure
 	// if crawler will discovered another link from edge destination it will need updated
-	UpsertEdge(edge *Edge) error
+	//
+	// edge that reference unknown link is rejected with ErrUnknownEdgeLinks,
+	// unless the graph use name-based IDs where placeholder links are created
+	// for unknown ID.
+	UpsertEdge(ctx context.Context, edge *Edge) error
+
+	// UpsertEdges insert or update a batch of edges, every edge is updated in
+	// place like UpsertEdge. edge that can't be inserted (e.g. unknown link)
+	// is reported through *BatchError while the rest of the batch is applied.
+	UpsertEdges(ctx context.Context, edges []*Edge) error
+
+	// LookupEdge return the edge with given ID, or ErrNotFound if there is
+	// no such edge.
+	LookupEdge(ctx context.Context, id uuid.UUID) (*Edge, error)
+
+	// Edges return iterator over edges whose source is in [fromID, toID),
+	// edges are ordered by source and then destination ID. range that end
+	// at MaxUUID include it, see Range. opts filter the returned edges, with
+	// AsOf the graph at a past time is returned instead and updateBefore is
+	// ignored.
+	Edges(ctx context.Context, fromID, toID uuid.UUID, updateBefore time.Time, opts ...EdgeOption) (EdgeIterator, error)
+
+	// OutEdges return iterator over edges that originate from linkID ordered
+	// by destination ID, unknown link has no edge.
+	OutEdges(ctx context.Context, linkID uuid.UUID) (EdgeIterator, error)
+
+	// InEdges return iterator over edges that end at linkID ordered by source
+	// ID, unknown link has no edge.
+	InEdges(ctx context.Context, linkID uuid.UUID) (EdgeIterator, error)
+
+	// UpsertRedirect insert the redirect of its alias or replace it, redirect
+	// is updated in place. zero ObservedAt is set to the current time. like
+	// UpsertEdge, redirect that reference unknown link is rejected with
+	// ErrUnknownEdgeLinks unless the graph use name-based IDs. redirect of a
+	// link to itself is rejected with ErrRedirectLoop, longer loop is only
+	// detected by ResolveRedirect. redirects are removed with their links.
+	UpsertRedirect(ctx context.Context, redirect *Redirect) error
+
+	// ResolveRedirect return the chain of redirects that start at linkID, the
+	// Target of the last one is the final target. link that does not
+	// redirect has empty chain. chain that loop return ErrRedirectLoop.
+	ResolveRedirect(ctx context.Context, linkID uuid.UUID) ([]*Redirect, error)
+
+	// UpdateScores store the score of kind of every link in scores, unknown
+	// link is ignored. invalid kind is rejected with ErrUnknownScoreKind.
+	// the scores supplied to UpsertLink are ignored and the stored ones are
+	// kept.
+	UpdateScores(ctx context.Context, kind ScoreKind, scores map[uuid.UUID]float64) error
 
-	// LookupEdge(id uuid.UUID) (*Edge, error)
+	// RemoveStaleEdges removes any edge that originates from the specified
+	// link ID and was updated before the specified timestamp, it return the
+	// removed edges. with DryRun option nothing is removed. graph that keep
+	// the edge history record the removed edges as vanished at
+	// updatedBefore, see AsOf.
+	RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time, opts ...RemoveOption) (*RemovedEdges, error)
+
+	// RemoveLink remove the link with given ID together with every edge that
+	// originate from or end at it, or return ErrNotFound if there is no such
+	// link.
+	RemoveLink(ctx context.Context, id uuid.UUID) error
+
+	// RemoveStaleLinks remove every link, and its edges, that was retrieved
+	// before retrievedBefore and return the number of removed links. link
+	// that is not retrieved yet (zero RetrievedAt) is kept.
+	RemoveStaleLinks(ctx context.Context, retrievedBefore time.Time) (int64, error)
+
+	// RemoveOrphanLinks remove every link that has neither incoming nor
+	// outgoing edge and was retrieved before retrievedBefore, it return the
+	// number of removed links. like RemoveStaleLinks, link that is not
+	// retrieved yet is kept.
+	RemoveOrphanLinks(ctx context.Context, retrievedBefore time.Time) (int64, error)
+}
 
-	Edges(fromID, toID uuid.UUID, updateBefore time.Time) (EdgeIterator, error)
+// RemovedEdges report the edges removed by RemoveStaleEdges.
+type RemovedEdges struct {
+	// number of removed edges
+	Count int64
 
-	// RemoveStaleEdges removes any edge that originates from the specified
-	// link ID and was updated before the specified timestamp.
-	RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error
+	// destination of the removed edges, in no particular order
+	DstIDs []uuid.UUID
 }
 
 // implemented by graph object that can be iterated
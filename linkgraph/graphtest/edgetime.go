@@ -0,0 +1,68 @@
+package graphtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// RunCallerEdgeTime executes the tests specific to graph that keep the edge
+// update_at supplied by the caller (linkgraph.CallerTime), e.g. to replay
+// crawl logs. it is run in addition to Run.
+func RunCallerEdgeTime(t *testing.T, newGraph Factory) {
+	runTests(t, newGraph, []testCase{
+		{"replay keep caller time", testCallerEdgeTime},
+		{"older replay", testCallerEdgeTimeOlderReplay},
+	})
+}
+
+func testCallerEdgeTime(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 2)
+
+	updateAt := time.Now().Add(-24 * time.Hour).Truncate(time.Second).UTC()
+	edge := &linkgraph.Edge{Src: ids[0], Dst: ids[1], UpdateAt: updateAt}
+	if err := g.UpsertEdge(ctx, edge); err != nil {
+		t.Fatal(err)
+	}
+	if !edge.UpdateAt.Equal(updateAt) || !edge.FirstSeen.Equal(updateAt) {
+		t.Fatalf("\ngot: %v %v\nexpect: %v", edge.UpdateAt, edge.FirstSeen, updateAt)
+	}
+
+	// edge without timestamp is stamped by the store clock
+	fresh := &linkgraph.Edge{Src: ids[1], Dst: ids[0]}
+	if err := g.UpsertEdge(ctx, fresh); err != nil {
+		t.Fatal(err)
+	}
+	if !fresh.UpdateAt.After(updateAt) {
+		t.Fatalf("\ngot: %v\nexpect: stamped by the store clock", fresh.UpdateAt)
+	}
+}
+
+func testCallerEdgeTimeOlderReplay(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 2)
+
+	updateAt := time.Now().Add(-24 * time.Hour).Truncate(time.Second).UTC()
+	edge := &linkgraph.Edge{Src: ids[0], Dst: ids[1], UpdateAt: updateAt, AnchorText: "newer"}
+	if err := g.UpsertEdge(ctx, edge); err != nil {
+		t.Fatal(err)
+	}
+
+	// older replay move first_seen backward only, update_at and the
+	// attributes of the newer crawl are kept
+	older := &linkgraph.Edge{Src: ids[0], Dst: ids[1], UpdateAt: updateAt.Add(-time.Hour), AnchorText: "older"}
+	if err := g.UpsertEdges(ctx, []*linkgraph.Edge{older}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := g.LookupEdge(ctx, edge.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []*linkgraph.Edge{older, stored} {
+		if e.ID != edge.ID || !e.UpdateAt.Equal(updateAt) || !e.FirstSeen.Equal(updateAt.Add(-time.Hour)) || e.AnchorText != "newer" {
+			t.Fatalf("\ngot: %+v\nexpect: update_at %v first_seen %v anchor %q", e, updateAt, updateAt.Add(-time.Hour), "newer")
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package graphtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// RunNameBasedIDs executes the tests specific to graph that assign name-based
+// IDs under namespace, see linkgraph.NameBasedID. it is run in addition to
+// Run.
+func RunNameBasedIDs(t *testing.T, namespace uuid.UUID, newGraph Factory) {
+	withNS := func(fn func(ctx context.Context, t *testing.T, ns uuid.UUID, g linkgraph.Graph)) func(context.Context, *testing.T, linkgraph.Graph) {
+		return func(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+			fn(ctx, t, namespace, g)
+		}
+	}
+
+	runTests(t, newGraph, []testCase{
+		{"link id derived from url", withNS(testNameBasedLinkID)},
+		{"link id mismatch", withNS(testNameBasedLinkIDMismatch)},
+		{"edge before links", withNS(testNameBasedEdgeBeforeLinks)},
+		{"edge batch before links", withNS(testNameBasedEdgesBeforeLinks)},
+		{"redirect before links", withNS(testNameBasedRedirectBeforeLinks)},
+	})
+}
+
+func testNameBasedLinkID(ctx context.Context, t *testing.T, ns uuid.UUID, g linkgraph.Graph) {
+	link := &linkgraph.Link{URL: "https://example.com/name-based", RetrievedAt: time.Now()}
+	if err := g.UpsertLink(ctx, link); err != nil {
+		t.Fatal(err)
+	}
+	if expect := linkgraph.NameBasedID(ns, link.URL); link.ID != expect {
+		t.Fatalf("\ngot: %v\nexpect: %v", link.ID, expect)
+	}
+
+	// the caller may supply the ID it computed
+	again := &linkgraph.Link{ID: link.ID, URL: link.URL}
+	if err := g.UpsertLink(ctx, again); err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != link.ID {
+		t.Fatalf("\ngot: %v\nexpect: %v", again.ID, link.ID)
+	}
+
+	links := []*linkgraph.Link{
+		{URL: "https://example.com/name-based/a"},
+		{URL: "https://example.com/name-based/b"},
+	}
+	if err := g.UpsertLinks(ctx, links); err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range links {
+		if expect := linkgraph.NameBasedID(ns, l.URL); l.ID != expect {
+			t.Fatalf("%s\ngot: %v\nexpect: %v", l.URL, l.ID, expect)
+		}
+	}
+}
+
+func testNameBasedLinkIDMismatch(ctx context.Context, t *testing.T, ns uuid.UUID, g linkgraph.Graph) {
+	link := &linkgraph.Link{ID: uuid.New(), URL: "https://example.com/mismatch"}
+	if err := g.UpsertLink(ctx, link); !errors.Is(err, linkgraph.ErrLinkIDMismatch) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrLinkIDMismatch)
+	}
+	if _, err := g.LookupLinkByURL(ctx, link.URL); !errors.Is(err, linkgraph.ErrNotFound) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+
+	// only the mismatched item of the batch fail
+	links := []*linkgraph.Link{
+		{URL: "https://example.com/mismatch/a"},
+		{ID: uuid.New(), URL: "https://example.com/mismatch/b"},
+	}
+	err := g.UpsertLinks(ctx, links)
+
+	var batchErr *linkgraph.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("\ngot: %v\nexpect: %T", err, batchErr)
+	}
+	if batchErr.Errs[0] != nil || !errors.Is(batchErr.Errs[1], linkgraph.ErrLinkIDMismatch) {
+		t.Fatalf("\ngot: %v\nexpect: [<nil> %v]", batchErr.Errs, linkgraph.ErrLinkIDMismatch)
+	}
+	if expect := linkgraph.NameBasedID(ns, links[0].URL); links[0].ID != expect {
+		t.Fatalf("\ngot: %v\nexpect: %v", links[0].ID, expect)
+	}
+}
+
+func testNameBasedEdgeBeforeLinks(ctx context.Context, t *testing.T, ns uuid.UUID, g linkgraph.Graph) {
+	srcURL, dstURL := "https://example.com/early/src", "https://example.com/early/dst"
+	edge := &linkgraph.Edge{
+		Src: linkgraph.NameBasedID(ns, srcURL),
+		Dst: linkgraph.NameBasedID(ns, dstURL),
+	}
+	if err := g.UpsertEdge(ctx, edge); err != nil {
+		t.Fatal(err)
+	}
+	if edge.ID == uuid.Nil {
+		t.Fatal("edge id not set")
+	}
+
+	// placeholder has no url and was never retrieved
+	placeholder, err := g.LookupLink(ctx, edge.Dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if placeholder.URL != "" || !placeholder.RetrievedAt.IsZero() {
+		t.Fatalf("\ngot: %+v\nexpect: placeholder link", placeholder)
+	}
+
+	// the crawler later upsert the links, they take over the placeholders
+	retrievedAt := time.Now().Truncate(time.Microsecond)
+	links := []*linkgraph.Link{
+		{URL: srcURL, RetrievedAt: retrievedAt},
+		{URL: dstURL},
+	}
+	if err := g.UpsertLinks(ctx, links); err != nil {
+		t.Fatal(err)
+	}
+	if links[0].ID != edge.Src || links[1].ID != edge.Dst {
+		t.Fatalf("\ngot: %v %v\nexpect: %v %v", links[0].ID, links[1].ID, edge.Src, edge.Dst)
+	}
+
+	src, err := g.LookupLinkByURL(ctx, srcURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.ID != edge.Src || !src.RetrievedAt.Equal(retrievedAt) {
+		t.Fatalf("\ngot: %+v\nexpect: %v retrieved at %v", src, edge.Src, retrievedAt)
+	}
+
+	got := collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))
+	if len(got) != 1 || got[0].ID != edge.ID {
+		t.Fatalf("\ngot: %v\nexpect: [%v]", got, edge.ID)
+	}
+}
+
+func testNameBasedEdgesBeforeLinks(ctx context.Context, t *testing.T, ns uuid.UUID, g linkgraph.Graph) {
+	known := &linkgraph.Link{URL: "https://example.com/batch/known"}
+	if err := g.UpsertLink(ctx, known); err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := linkgraph.NameBasedID(ns, "https://example.com/batch/unknown")
+	edges := []*linkgraph.Edge{
+		{Src: known.ID, Dst: unknown},
+		{Src: unknown, Dst: known.ID},
+	}
+	if err := g.UpsertEdges(ctx, edges); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, edge := range edges {
+		if _, err := g.LookupEdge(ctx, edge.ID); err != nil {
+			t.Fatalf("%v: %v", edge.ID, err)
+		}
+	}
+	if _, err := g.LookupLink(ctx, unknown); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testNameBasedRedirectBeforeLinks(ctx context.Context, t *testing.T, ns uuid.UUID, g linkgraph.Graph) {
+	redirect := &linkgraph.Redirect{
+		Alias:      linkgraph.NameBasedID(ns, "http://example.com/moved"),
+		Target:     linkgraph.NameBasedID(ns, "https://example.com/moved"),
+		StatusCode: 301,
+	}
+	if err := g.UpsertRedirect(ctx, redirect); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []uuid.UUID{redirect.Alias, redirect.Target} {
+		if _, err := g.LookupLink(ctx, id); err != nil {
+			t.Fatalf("%v: %v", id, err)
+		}
+	}
+	chain, err := g.ResolveRedirect(ctx, redirect.Alias)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 1 || chain[0].Target != redirect.Target {
+		t.Fatalf("\ngot: %+v\nexpect: [%+v]", chain, redirect)
+	}
+}
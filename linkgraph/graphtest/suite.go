@@ -0,0 +1,1514 @@
+// Package graphtest provides a conformance test suite that every
+// linkgraph.Graph implementation is expected to pass.
+//
+// A backend test only need to provide a factory that return an empty graph:
+//
+//	func TestGraph(t *testing.T) {
+//		graphtest.Run(t, func(t *testing.T) linkgraph.Graph {
+//			return memory.New()
+//		})
+//	}
+//
+// the graph is expected to keep the edge history, see linkgraph.AsOf.
+package graphtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// Factory return an empty graph to run a single test against.
+// Any cleanup should be registered with t.Cleanup.
+type Factory func(t *testing.T) linkgraph.Graph
+
+var maxUUID = linkgraph.MaxUUID
+
+// Run executes the whole suite against graph created by newGraph.
+func Run(t *testing.T, newGraph Factory) {
+	runTests(t, newGraph, []testCase{
+		{"link upsert idempotency", testUpsertLinkIdempotency},
+		{"link retrievedAt monotonicity", testUpsertLinkRetrievedAt},
+		{"link batch upsert", testUpsertLinks},
+		{"link lookup", testLookupLink},
+		{"link time zone", testLinkTimeZone},
+		{"link metadata", testLinkMetadata},
+		{"link lookup by url", testLookupLinkByURL},
+		{"resolve urls", testResolveURLs},
+		{"link iterator time filter", testLinkIteratorTimeFilter},
+		{"partitioned link iterator", testPartitionedLinkIterators},
+		{"concurrent link iterators", testConcurrentLinkIterators},
+		{"parallel link scan", testScanLinks},
+		{"edge upsert and refresh", testUpsertEdge},
+		{"edge with unknown link", testUpsertEdgeUnknownLinks},
+		{"edge batch upsert", testUpsertEdges},
+		{"edge lookup", testLookupEdge},
+		{"edge attributes", testEdgeAttributes},
+		{"edge iterator time filter", testEdgeIteratorTimeFilter},
+		{"partitioned edge iterator", testPartitionedEdgeIterators},
+		{"concurrent edge iterators", testConcurrentEdgeIterators},
+		{"parallel edge scan", testScanEdges},
+		{"out and in edges", testNeighborEdges},
+		{"remove stale edges", testRemoveStaleEdges},
+		{"edge history", testEdgeHistory},
+		{"link scores", testUpdateScores},
+		{"redirect upsert", testUpsertRedirect},
+		{"redirect chain", testResolveRedirect},
+		{"edges resolve redirects", testEdgesResolveRedirects},
+		{"remove link", testRemoveLink},
+		{"remove stale links", testRemoveStaleLinks},
+		{"remove orphan links", testRemoveOrphanLinks},
+		{"cancelled context", testCancelledContext},
+	})
+}
+
+type testCase struct {
+	name string
+	fn   func(ctx context.Context, t *testing.T, g linkgraph.Graph)
+}
+
+// runTests run every test against its own graph created by newGraph.
+func runTests(t *testing.T, newGraph Factory, tests []testCase) {
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			tc.fn(ctx, t, newGraph(t))
+		})
+	}
+}
+
+func testUpsertLinkIdempotency(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	original := &linkgraph.Link{
+		URL:         "https://example.com",
+		RetrievedAt: time.Now().Add(-10 * time.Hour),
+	}
+	if err := g.UpsertLink(ctx, original); err != nil {
+		t.Fatal(err)
+	}
+	if original.ID == uuid.Nil {
+		t.Fatal("expected a linkID to be assigned to the new link")
+	}
+
+	// upsert the same url, with or without ID, must not create new link
+	for _, id := range []uuid.UUID{uuid.Nil, original.ID, uuid.New()} {
+		same := &linkgraph.Link{
+			ID:          id,
+			URL:         original.URL,
+			RetrievedAt: original.RetrievedAt,
+		}
+		if err := g.UpsertLink(ctx, same); err != nil {
+			t.Fatal(err)
+		}
+		if same.ID != original.ID {
+			t.Fatalf("\ngot:\t %v, \nexpected:\t %v \nerror: %v", same.ID, original.ID,
+				"upsert with same url assign different ID")
+		}
+	}
+
+	other := &linkgraph.Link{URL: "https://example.com/other"}
+	if err := g.UpsertLink(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	if other.ID == uuid.Nil || other.ID == original.ID {
+		t.Fatalf("\ngot:\t %v \nerror: %v", other.ID, "expected a new linkID to be assigned")
+	}
+
+	if n := len(collectLinks(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))); n != 2 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", n, 2)
+	}
+}
+
+func testUpsertLinkRetrievedAt(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	original := &linkgraph.Link{
+		URL:         "https://example.com",
+		RetrievedAt: time.Now().Add(-10 * time.Hour),
+	}
+	if err := g.UpsertLink(ctx, original); err != nil {
+		t.Fatal(err)
+	}
+
+	// newer timestamp move retrieved_at forward
+	accessedAt := time.Now().Truncate(time.Second).UTC()
+	existing := &linkgraph.Link{URL: original.URL, RetrievedAt: accessedAt}
+	if err := g.UpsertLink(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+	if !existing.RetrievedAt.Equal(accessedAt) {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v \nerror: %v", existing.RetrievedAt, accessedAt,
+			"last accessed timestamp was not updated")
+	}
+
+	// older timestamp must not overwrite the newer one
+	older := &linkgraph.Link{URL: original.URL, RetrievedAt: accessedAt.Add(-time.Hour)}
+	if err := g.UpsertLink(ctx, older); err != nil {
+		t.Fatal(err)
+	}
+	if !older.RetrievedAt.Equal(accessedAt) {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v \nerror: %v", older.RetrievedAt, accessedAt,
+			"last accessed timestamp was overwritten with an older value")
+	}
+
+	links := collectLinks(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))
+	if len(links) != 1 || !links[0].RetrievedAt.Equal(accessedAt) {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", links, accessedAt)
+	}
+}
+
+func testUpsertLinks(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	existing := &linkgraph.Link{URL: "https://example.com/0", RetrievedAt: time.Now().Add(-time.Hour)}
+	if err := g.UpsertLink(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Truncate(time.Second).UTC()
+	batch := []*linkgraph.Link{
+		{URL: "https://example.com/0", RetrievedAt: now},
+		{URL: "https://example.com/1", RetrievedAt: now.Add(-time.Minute)},
+		{URL: "https://example.com/2", RetrievedAt: now},
+		// duplicate url within the same batch
+		{URL: "https://example.com/1", RetrievedAt: now},
+	}
+	if err := g.UpsertLinks(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+
+	if batch[0].ID != existing.ID {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v \nerror: %v", batch[0].ID, existing.ID,
+			"batch upsert of existing url assign different ID")
+	}
+	if batch[1].ID == uuid.Nil || batch[1].ID != batch[3].ID {
+		t.Fatalf("\ngot:\t %v and %v \nerror: %v", batch[1].ID, batch[3].ID,
+			"duplicate url within a batch must share the same ID")
+	}
+	if batch[2].ID == uuid.Nil || batch[2].ID == batch[1].ID {
+		t.Fatalf("\ngot:\t %v \nerror: %v", batch[2].ID, "expected a new linkID to be assigned")
+	}
+	for i, link := range batch {
+		if !link.RetrievedAt.Equal(now) {
+			t.Fatalf("\ngot:\t %v, \nexpected:\t %v \nerror: link %d retrieved_at not updated", link.RetrievedAt, now, i)
+		}
+	}
+
+	if n := len(collectLinks(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))); n != 3 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", n, 3)
+	}
+	if err := g.UpsertLinks(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testLookupLink(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	link := &linkgraph.Link{
+		URL:         "https://example.com",
+		RetrievedAt: time.Now().Truncate(time.Second).UTC(),
+	}
+	if err := g.UpsertLink(ctx, link); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := g.LookupLink(ctx, link.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID != link.ID || other.URL != link.URL || !other.RetrievedAt.Equal(link.RetrievedAt) {
+		t.Fatalf("\ngot:\t %+v, \nexpected:\t %+v \nerror: %v", other, link,
+			"lookup by ID returned the wrong link")
+	}
+
+	if _, err := g.LookupLink(ctx, uuid.New()); !errors.Is(err, linkgraph.ErrNotFound) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+}
+
+func testLinkTimeZone(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	zone := time.FixedZone("UTC+7", 7*60*60)
+	retrievedAt := time.Now().Truncate(time.Second).In(zone)
+
+	link := &linkgraph.Link{URL: "https://example.com", RetrievedAt: retrievedAt}
+	if err := g.UpsertLink(ctx, link); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := g.LookupLink(ctx, link.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !other.RetrievedAt.Equal(retrievedAt) || other.RetrievedAt.Location() != time.UTC {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v in UTC", other.RetrievedAt, retrievedAt)
+	}
+
+	// the filter is an instant, the zone it is expressed in does not matter
+	links := collectLinks(ctx, t, g, uuid.Nil, maxUUID, retrievedAt.Add(time.Second).In(zone))
+	if len(links) != 1 {
+		t.Fatalf("\ngot: %d links\nexpected: 1", len(links))
+	}
+	links = collectLinks(ctx, t, g, uuid.Nil, maxUUID, retrievedAt.In(zone))
+	if len(links) != 0 {
+		t.Fatalf("\ngot: %d links\nexpected: 0", len(links))
+	}
+
+	// link that is not retrieved yet keep the zero time
+	pending := &linkgraph.Link{URL: "https://example.com/pending"}
+	if err := g.UpsertLink(ctx, pending); err != nil {
+		t.Fatal(err)
+	}
+	if other, err = g.LookupLink(ctx, pending.ID); err != nil {
+		t.Fatal(err)
+	}
+	if !other.RetrievedAt.IsZero() {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t zero time", other.RetrievedAt)
+	}
+}
+
+func testLinkMetadata(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	retrievedAt := time.Now().Truncate(time.Second).UTC()
+
+	link := &linkgraph.Link{
+		URL:           "https://example.com",
+		RetrievedAt:   retrievedAt,
+		StatusCode:    200,
+		ContentType:   "text/html",
+		ContentLength: 1024,
+		ContentHash:   []byte{0xde, 0xad, 0xbe, 0xef},
+		Title:         "Example",
+		LastModified:  retrievedAt.Add(-24 * time.Hour),
+		ETag:          `"v1"`,
+	}
+	expected := *link
+	if err := g.UpsertLink(ctx, link); err != nil {
+		t.Fatal(err)
+	}
+	expected.ID = link.ID
+	assertSameLink(t, link, &expected)
+
+	other, err := g.LookupLink(ctx, link.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameLink(t, other, &expected)
+
+	// discovering the link again or replaying older retrieval keep metadata
+	discovered := &linkgraph.Link{URL: link.URL}
+	if err := g.UpsertLink(ctx, discovered); err != nil {
+		t.Fatal(err)
+	}
+	assertSameLink(t, discovered, &expected)
+
+	older := &linkgraph.Link{URL: link.URL, RetrievedAt: retrievedAt.Add(-time.Hour), StatusCode: 500}
+	if err := g.UpsertLinks(ctx, []*linkgraph.Link{older}); err != nil {
+		t.Fatal(err)
+	}
+	assertSameLink(t, older, &expected)
+
+	// newer retrieval replace every metadata field
+	newer := &linkgraph.Link{
+		URL:         link.URL,
+		RetrievedAt: retrievedAt.Add(time.Hour),
+		StatusCode:  304,
+		ETag:        `"v1"`,
+	}
+	expected = *newer
+	if err := g.UpsertLinks(ctx, []*linkgraph.Link{newer}); err != nil {
+		t.Fatal(err)
+	}
+	expected.ID = link.ID
+	assertSameLink(t, newer, &expected)
+
+	links := collectLinks(ctx, t, g, uuid.Nil, maxUUID, retrievedAt.Add(2*time.Hour))
+	if len(links) != 1 {
+		t.Fatalf("\ngot: %d links\nexpected: 1", len(links))
+	}
+	assertSameLink(t, links[0], &expected)
+}
+
+func assertSameLink(t *testing.T, got, expected *linkgraph.Link) {
+	t.Helper()
+
+	same := got.ID == expected.ID &&
+		got.URL == expected.URL &&
+		got.RetrievedAt.Equal(expected.RetrievedAt) &&
+		got.StatusCode == expected.StatusCode &&
+		got.ContentType == expected.ContentType &&
+		got.ContentLength == expected.ContentLength &&
+		bytes.Equal(got.ContentHash, expected.ContentHash) &&
+		got.Title == expected.Title &&
+		got.LastModified.Equal(expected.LastModified) &&
+		got.ETag == expected.ETag
+	if !same {
+		t.Fatalf("\ngot:\t %+v, \nexpected:\t %+v", got, expected)
+	}
+}
+
+func testLookupLinkByURL(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	retrievedAt := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	link := &linkgraph.Link{URL: "https://example.com", RetrievedAt: retrievedAt}
+	if err := g.UpsertLink(ctx, link); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := g.LookupLinkByURL(ctx, link.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID != link.ID {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", other.ID, link.ID)
+	}
+
+	// lookup must be read-only
+	if !other.RetrievedAt.Equal(retrievedAt) {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v \nerror: %v", other.RetrievedAt, retrievedAt,
+			"lookup by url modified retrieved_at")
+	}
+
+	if _, err := g.LookupLinkByURL(ctx, "https://example.com/unknown"); !errors.Is(err, linkgraph.ErrNotFound) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+	if n := len(collectLinks(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))); n != 1 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", n, 1)
+	}
+}
+
+func testResolveURLs(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 3)
+
+	urls := []string{
+		"https://example.com/0",
+		"https://example.com/2",
+		"https://example.com/unknown",
+	}
+	resolved, err := g.ResolveURLs(ctx, urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]uuid.UUID{
+		"https://example.com/0": ids[0],
+		"https://example.com/2": ids[2],
+	}
+	if len(resolved) != len(expected) {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", resolved, expected)
+	}
+	for url, id := range expected {
+		if resolved[url] != id {
+			t.Fatalf("\ngot:\t %v, \nexpected:\t %v", resolved, expected)
+		}
+	}
+
+	resolved, err = g.ResolveURLs(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 0 {
+		t.Fatalf("\ngot:\t %v, \nexpected empty result", resolved)
+	}
+}
+
+func testLinkIteratorTimeFilter(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	now := time.Now().Truncate(time.Second).UTC()
+
+	ids := make([]uuid.UUID, 3)
+	for i := range ids {
+		link := &linkgraph.Link{
+			URL:         fmt.Sprint(i),
+			RetrievedAt: now.Add(time.Duration(i) * time.Minute),
+		}
+		if err := g.UpsertLink(ctx, link); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = link.ID
+	}
+
+	for i := range ids {
+		filter := now.Add(time.Duration(i)*time.Minute + time.Second)
+		var got []uuid.UUID
+		for _, link := range collectLinks(ctx, t, g, uuid.Nil, maxUUID, filter) {
+			got = append(got, link.ID)
+		}
+		assertSameIDs(t, got, ids[:i+1])
+	}
+}
+
+func testPartitionedLinkIterators(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	numLinks := 100
+	ids := make([]uuid.UUID, numLinks)
+	for i := range ids {
+		link := &linkgraph.Link{URL: fmt.Sprint(i)}
+		if err := g.UpsertLink(ctx, link); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = link.ID
+	}
+
+	for _, numPartition := range []int{1, 2, 7, 16} {
+		var got []uuid.UUID
+		ranges, err := linkgraph.Partition(numPartition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range ranges {
+			from, to := r.From, r.To
+			for _, link := range collectLinks(ctx, t, g, from, to, time.Now().Add(time.Hour)) {
+				if link.ID.String() < from.String() || link.ID.String() >= to.String() {
+					t.Fatalf("link %v outside of range [%v,%v)", link.ID, from, to)
+				}
+				got = append(got, link.ID)
+			}
+		}
+		assertSameIDs(t, got, ids)
+	}
+}
+
+func testScanLinks(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 50)
+
+	ranges, err := linkgraph.Partition(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu  sync.Mutex
+		got []uuid.UUID
+	)
+	err = linkgraph.ScanLinks(ctx, g, ranges, 3, time.Now().Add(time.Hour), func(r linkgraph.Range, link *linkgraph.Link) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, link.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameIDs(t, got, ids)
+
+	// error of fn stop its range and is reported
+	errStop := errors.New("stop")
+	err = linkgraph.ScanLinks(ctx, g, ranges, 3, time.Now().Add(time.Hour), func(r linkgraph.Range, link *linkgraph.Link) error {
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, errStop)
+	}
+}
+
+func testConcurrentLinkIterators(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	var (
+		wg           sync.WaitGroup
+		numIterators = 10
+		numLinks     = 100
+	)
+
+	for i := 0; i < numLinks; i++ {
+		link := &linkgraph.Link{URL: fmt.Sprint(i)}
+		if err := g.UpsertLink(ctx, link); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errC := make(chan error, numIterators)
+	wg.Add(numIterators)
+	for i := 0; i < numIterators; i++ {
+		go func(id int) {
+			defer wg.Done()
+
+			it, err := g.Links(ctx, uuid.Nil, maxUUID, time.Now().Add(time.Hour))
+			if err != nil {
+				errC <- fmt.Errorf("iterator %d: %v", id, err)
+				return
+			}
+			defer func() { _ = it.Close() }()
+
+			seen := make(map[uuid.UUID]bool)
+			for it.Next() {
+				linkID := it.Link().ID
+				if seen[linkID] {
+					errC <- fmt.Errorf("iterator %d saw same link twice", id)
+					return
+				}
+				seen[linkID] = true
+			}
+			if err := it.Error(); err != nil {
+				errC <- fmt.Errorf("iterator %d: %v", id, err)
+				return
+			}
+			if len(seen) != numLinks {
+				errC <- fmt.Errorf("iterator %d got:%v, expected: %v", id, len(seen), numLinks)
+			}
+		}(i)
+	}
+
+	waitIterators(t, &wg, errC)
+}
+
+func testUpsertEdge(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 3)
+
+	original := &linkgraph.Edge{Src: ids[0], Dst: ids[1]}
+	if err := g.UpsertEdge(ctx, original); err != nil {
+		t.Fatal(err)
+	}
+	if original.ID == uuid.Nil {
+		t.Fatal("orginal edge id not set")
+	}
+	if original.UpdateAt.IsZero() {
+		t.Fatal("orginal updateAt field not set")
+	}
+
+	// upsert the same (src,dst) pair refresh update_at and keep the ID
+	time.Sleep(10 * time.Millisecond)
+	other := &linkgraph.Edge{Src: ids[0], Dst: ids[1]}
+	if err := g.UpsertEdge(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	if other.ID != original.ID {
+		t.Fatalf("\ngot:%v\nExpect:%v\n:message:%v", other.ID, original.ID,
+			"orginal edge id change while updating (upsert)")
+	}
+	if !other.UpdateAt.After(original.UpdateAt) {
+		t.Fatalf("\ngot:%v\nExpect after:%v\nmessage:%v", other.UpdateAt, original.UpdateAt,
+			"orginal edge UpdateAt field not modified")
+	}
+
+	// reversed direction is a different edge
+	reversed := &linkgraph.Edge{Src: ids[1], Dst: ids[0]}
+	if err := g.UpsertEdge(ctx, reversed); err != nil {
+		t.Fatal(err)
+	}
+	if reversed.ID == original.ID {
+		t.Fatal("edge with reversed direction share the same ID")
+	}
+
+	if n := len(collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))); n != 2 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", n, 2)
+	}
+}
+
+func testUpsertEdgeUnknownLinks(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 1)
+
+	for _, edge := range []*linkgraph.Edge{
+		{Src: ids[0], Dst: uuid.New()},
+		{Src: uuid.New(), Dst: ids[0]},
+	} {
+		err := g.UpsertEdge(ctx, edge)
+		if !errors.Is(err, linkgraph.ErrUnknownEdgeLinks) {
+			t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrUnknownEdgeLinks)
+		}
+	}
+}
+
+func testUpsertEdges(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 3)
+
+	existing := &linkgraph.Edge{Src: ids[0], Dst: ids[1]}
+	if err := g.UpsertEdge(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	batch := []*linkgraph.Edge{
+		{Src: ids[0], Dst: ids[1]},
+		{Src: ids[0], Dst: uuid.New()},
+		{Src: ids[1], Dst: ids[2]},
+		// duplicate pair within the same batch
+		{Src: ids[1], Dst: ids[2]},
+	}
+	err := g.UpsertEdges(ctx, batch)
+
+	var batchErr *linkgraph.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("\ngot: %v\nexpect: %T", err, batchErr)
+	}
+	if len(batchErr.Errs) != len(batch) {
+		t.Fatalf("\ngot: %v\nexpect: %v errors", len(batchErr.Errs), len(batch))
+	}
+	for i, itemErr := range batchErr.Errs {
+		if i == 1 {
+			if !errors.Is(itemErr, linkgraph.ErrUnknownEdgeLinks) {
+				t.Fatalf("\ngot: %v\nexpect: %v", itemErr, linkgraph.ErrUnknownEdgeLinks)
+			}
+			continue
+		}
+		if itemErr != nil {
+			t.Fatalf("edge %d: %v", i, itemErr)
+		}
+	}
+
+	if batch[0].ID != existing.ID || !batch[0].UpdateAt.After(existing.UpdateAt) {
+		t.Fatalf("\ngot:\t %+v, \nexpected refreshed:\t %+v", batch[0], existing)
+	}
+	if batch[2].ID == uuid.Nil || batch[2].ID != batch[3].ID {
+		t.Fatalf("\ngot:\t %v and %v \nerror: %v", batch[2].ID, batch[3].ID,
+			"duplicate pair within a batch must share the same ID")
+	}
+
+	if n := len(collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))); n != 2 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", n, 2)
+	}
+}
+
+func testLookupEdge(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 2)
+
+	edge := &linkgraph.Edge{Src: ids[0], Dst: ids[1]}
+	if err := g.UpsertEdge(ctx, edge); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := g.LookupEdge(ctx, edge.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID != edge.ID || other.Src != edge.Src || other.Dst != edge.Dst || !other.UpdateAt.Equal(edge.UpdateAt) {
+		t.Fatalf("\ngot:\t %+v, \nexpected:\t %+v \nerror: %v", other, edge,
+			"lookup by ID returned the wrong edge")
+	}
+
+	if _, err := g.LookupEdge(ctx, uuid.New()); !errors.Is(err, linkgraph.ErrNotFound) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+}
+
+func testEdgeAttributes(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 3)
+
+	edge := &linkgraph.Edge{
+		Src:         ids[0],
+		Dst:         ids[1],
+		AnchorText:  "example",
+		Rel:         linkgraph.RelNofollow | linkgraph.RelUGC,
+		Boilerplate: true,
+		Occurrences: 3,
+	}
+	expected := *edge
+	if err := g.UpsertEdge(ctx, edge); err != nil {
+		t.Fatal(err)
+	}
+	expected.ID, expected.UpdateAt = edge.ID, edge.UpdateAt
+	assertSameEdge(t, edge, &expected)
+
+	other, err := g.LookupEdge(ctx, edge.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameEdge(t, other, &expected)
+
+	// later upsert replace the attributes
+	time.Sleep(10 * time.Millisecond)
+	updated := &linkgraph.Edge{Src: ids[0], Dst: ids[1], AnchorText: "other", Rel: linkgraph.RelSponsored, Occurrences: 1}
+	expected = *updated
+	if err := g.UpsertEdges(ctx, []*linkgraph.Edge{updated}); err != nil {
+		t.Fatal(err)
+	}
+	expected.ID, expected.UpdateAt = edge.ID, updated.UpdateAt
+	assertSameEdge(t, updated, &expected)
+
+	nofollow := &linkgraph.Edge{Src: ids[1], Dst: ids[2], Rel: linkgraph.RelNofollow}
+	if err := g.UpsertEdge(ctx, nofollow); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now().Add(time.Hour)
+	edges := collectEdges(ctx, t, g, uuid.Nil, maxUUID, before)
+	if len(edges) != 2 {
+		t.Fatalf("\ngot: %d edges\nexpected: 2", len(edges))
+	}
+
+	edges = collectEdges(ctx, t, g, uuid.Nil, maxUUID, before, linkgraph.SkipNofollow())
+	if len(edges) != 1 {
+		t.Fatalf("\ngot: %d edges\nexpected: 1\nmessage: nofollow edge not skipped", len(edges))
+	}
+	assertSameEdge(t, edges[0], &expected)
+}
+
+func assertSameEdge(t *testing.T, got, expected *linkgraph.Edge) {
+	t.Helper()
+
+	same := got.ID == expected.ID &&
+		got.Src == expected.Src &&
+		got.Dst == expected.Dst &&
+		got.UpdateAt.Equal(expected.UpdateAt) &&
+		got.AnchorText == expected.AnchorText &&
+		got.Rel == expected.Rel &&
+		got.Boilerplate == expected.Boilerplate &&
+		got.Occurrences == expected.Occurrences
+	if !same {
+		t.Fatalf("\ngot:\t %+v, \nexpected:\t %+v", got, expected)
+	}
+}
+
+func testEdgeIteratorTimeFilter(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 3)
+
+	edgeIDs := make([]uuid.UUID, 0, 2)
+	edgeInsertTime := make([]time.Time, 0, 2)
+	for i := 1; i < len(ids); i++ {
+		edge := &linkgraph.Edge{Src: ids[0], Dst: ids[i]}
+		if err := g.UpsertEdge(ctx, edge); err != nil {
+			t.Fatal(err)
+		}
+		edgeIDs = append(edgeIDs, edge.ID)
+		edgeInsertTime = append(edgeInsertTime, edge.UpdateAt)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for i, updateAt := range edgeInsertTime {
+		var got []uuid.UUID
+		for _, edge := range collectEdges(ctx, t, g, uuid.Nil, maxUUID, updateAt.Add(time.Millisecond)) {
+			got = append(got, edge.ID)
+		}
+		assertSameIDs(t, got, edgeIDs[:i+1])
+	}
+}
+
+func testPartitionedEdgeIterators(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 50)
+
+	var edgeIDs []uuid.UUID
+	for i := range ids {
+		edge := &linkgraph.Edge{Src: ids[i], Dst: ids[(i+1)%len(ids)]}
+		if err := g.UpsertEdge(ctx, edge); err != nil {
+			t.Fatal(err)
+		}
+		edgeIDs = append(edgeIDs, edge.ID)
+	}
+
+	for _, numPartition := range []int{1, 2, 7, 16} {
+		var got []uuid.UUID
+		ranges, err := linkgraph.Partition(numPartition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range ranges {
+			from, to := r.From, r.To
+			for _, edge := range collectEdges(ctx, t, g, from, to, time.Now().Add(time.Hour)) {
+				if edge.Src.String() < from.String() || edge.Src.String() >= to.String() {
+					t.Fatalf("edge src %v outside of range [%v,%v)", edge.Src, from, to)
+				}
+				got = append(got, edge.ID)
+			}
+		}
+		assertSameIDs(t, got, edgeIDs)
+	}
+}
+
+func testScanEdges(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 50)
+
+	var expected []uuid.UUID
+	for i := range ids {
+		edge := &linkgraph.Edge{Src: ids[i], Dst: ids[(i+1)%len(ids)]}
+		if err := g.UpsertEdge(ctx, edge); err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, edge.ID)
+	}
+
+	ranges, err := linkgraph.Partition(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu  sync.Mutex
+		got []uuid.UUID
+	)
+	err = linkgraph.ScanEdges(ctx, g, ranges, 3, time.Now().Add(time.Hour), func(r linkgraph.Range, edge *linkgraph.Edge) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, edge.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameIDs(t, got, expected)
+}
+
+func testConcurrentEdgeIterators(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	var (
+		wg           sync.WaitGroup
+		numIterators = 10
+		numEdges     = 100
+	)
+
+	ids := createLinks(ctx, t, g, numEdges)
+	for i := range ids {
+		edge := &linkgraph.Edge{Src: ids[i], Dst: ids[(i+1)%len(ids)]}
+		if err := g.UpsertEdge(ctx, edge); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errC := make(chan error, numIterators)
+	wg.Add(numIterators)
+	for i := 0; i < numIterators; i++ {
+		go func(id int) {
+			defer wg.Done()
+
+			it, err := g.Edges(ctx, uuid.Nil, maxUUID, time.Now().Add(time.Hour))
+			if err != nil {
+				errC <- fmt.Errorf("iterator %d: %v", id, err)
+				return
+			}
+			defer func() { _ = it.Close() }()
+
+			seen := make(map[uuid.UUID]bool)
+			for it.Next() {
+				edgeID := it.Edge().ID
+				if seen[edgeID] {
+					errC <- fmt.Errorf("iterator %d saw same edge twice", id)
+					return
+				}
+				seen[edgeID] = true
+			}
+			if err := it.Error(); err != nil {
+				errC <- fmt.Errorf("iterator %d: %v", id, err)
+				return
+			}
+			if len(seen) != numEdges {
+				errC <- fmt.Errorf("iterator %d got:%v, expected: %v", id, len(seen), numEdges)
+			}
+		}(i)
+	}
+
+	waitIterators(t, &wg, errC)
+}
+
+func testNeighborEdges(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 5)
+
+	// 0 -> 1, 0 -> 2, 3 -> 0, 4 -> 0, 1 -> 2
+	edges := map[[2]int]uuid.UUID{}
+	for _, pair := range [][2]int{{0, 1}, {0, 2}, {3, 0}, {4, 0}, {1, 2}} {
+		edge := &linkgraph.Edge{Src: ids[pair[0]], Dst: ids[pair[1]]}
+		if err := g.UpsertEdge(ctx, edge); err != nil {
+			t.Fatal(err)
+		}
+		edges[pair] = edge.ID
+	}
+
+	collect := func(it linkgraph.EdgeIterator, err error, key func(*linkgraph.Edge) uuid.UUID) []uuid.UUID {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer it.Close()
+
+		var got []uuid.UUID
+		prev := ""
+		for it.Next() {
+			if k := key(it.Edge()).String(); k <= prev {
+				t.Fatalf("edge %v out of order", it.Edge().ID)
+			} else {
+				prev = k
+			}
+			got = append(got, it.Edge().ID)
+		}
+		if err := it.Error(); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	dstKey := func(e *linkgraph.Edge) uuid.UUID { return e.Dst }
+	srcKey := func(e *linkgraph.Edge) uuid.UUID { return e.Src }
+
+	it, err := g.OutEdges(ctx, ids[0])
+	assertSameIDs(t, collect(it, err, dstKey), []uuid.UUID{edges[[2]int{0, 1}], edges[[2]int{0, 2}]})
+
+	it, err = g.InEdges(ctx, ids[0])
+	assertSameIDs(t, collect(it, err, srcKey), []uuid.UUID{edges[[2]int{3, 0}], edges[[2]int{4, 0}]})
+
+	it, err = g.InEdges(ctx, ids[2])
+	assertSameIDs(t, collect(it, err, srcKey), []uuid.UUID{edges[[2]int{0, 2}], edges[[2]int{1, 2}]})
+
+	// link without edge, or unknown link
+	it, err = g.OutEdges(ctx, ids[2])
+	assertSameIDs(t, collect(it, err, dstKey), nil)
+	it, err = g.InEdges(ctx, uuid.New())
+	assertSameIDs(t, collect(it, err, srcKey), nil)
+}
+
+func testRemoveStaleEdges(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 4)
+
+	stale := &linkgraph.Edge{Src: ids[0], Dst: ids[1]}
+	if err := g.UpsertEdge(ctx, stale); err != nil {
+		t.Fatal(err)
+	}
+	// edge from another link must not be touched
+	otherSrc := &linkgraph.Edge{Src: ids[1], Dst: ids[2]}
+	if err := g.UpsertEdge(ctx, otherSrc); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	fresh := &linkgraph.Edge{Src: ids[0], Dst: ids[3]}
+	if err := g.UpsertEdge(ctx, fresh); err != nil {
+		t.Fatal(err)
+	}
+
+	// dry run report the stale edge but keep it
+	removed, err := g.RemoveStaleEdges(ctx, ids[0], fresh.UpdateAt, linkgraph.DryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed.Count != 1 || len(removed.DstIDs) != 1 || removed.DstIDs[0] != ids[1] {
+		t.Fatalf("\ngot:\t %+v, \nexpected:\t 1 edge to %v", removed, ids[1])
+	}
+	if n := len(collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))); n != 3 {
+		t.Fatalf("\ngot:\t %v edges, \nexpected:\t %v", n, 3)
+	}
+
+	removed, err = g.RemoveStaleEdges(ctx, ids[0], fresh.UpdateAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed.Count != 1 || len(removed.DstIDs) != 1 || removed.DstIDs[0] != ids[1] {
+		t.Fatalf("\ngot:\t %+v, \nexpected:\t 1 edge to %v", removed, ids[1])
+	}
+
+	// nothing left to remove
+	if removed, err = g.RemoveStaleEdges(ctx, ids[0], fresh.UpdateAt); err != nil {
+		t.Fatal(err)
+	}
+	if removed.Count != 0 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", removed.Count, 0)
+	}
+
+	var got []uuid.UUID
+	for _, edge := range collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour)) {
+		got = append(got, edge.ID)
+	}
+	assertSameIDs(t, got, []uuid.UUID{otherSrc.ID, fresh.ID})
+}
+
+func testEdgeHistory(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 3)
+
+	vanishing := &linkgraph.Edge{Src: ids[0], Dst: ids[1]}
+	if err := g.UpsertEdge(ctx, vanishing); err != nil {
+		t.Fatal(err)
+	}
+	if !vanishing.FirstSeen.Equal(vanishing.UpdateAt) {
+		t.Fatalf("\ngot: %v\nexpect: %v", vanishing.FirstSeen, vanishing.UpdateAt)
+	}
+	firstSeen := vanishing.FirstSeen
+
+	// seeing the edge again only move the last seen time
+	time.Sleep(10 * time.Millisecond)
+	if err := g.UpsertEdge(ctx, vanishing); err != nil {
+		t.Fatal(err)
+	}
+	if !vanishing.FirstSeen.Equal(firstSeen) || !vanishing.UpdateAt.After(firstSeen) {
+		t.Fatalf("\ngot: %v %v\nexpect first seen: %v", vanishing.FirstSeen, vanishing.UpdateAt, firstSeen)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	appearing := &linkgraph.Edge{Src: ids[0], Dst: ids[2]}
+	if err := g.UpsertEdge(ctx, appearing); err != nil {
+		t.Fatal(err)
+	}
+
+	// next crawl of the source only find the new edge
+	time.Sleep(10 * time.Millisecond)
+	if err := g.UpsertEdge(ctx, appearing); err != nil {
+		t.Fatal(err)
+	}
+	crawledAt := appearing.UpdateAt
+	if _, err := g.RemoveStaleEdges(ctx, ids[0], crawledAt); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		asOf     time.Time
+		expected []uuid.UUID
+	}{
+		{firstSeen.Add(-time.Millisecond), nil},
+		{firstSeen, []uuid.UUID{vanishing.ID}},
+		{appearing.FirstSeen, []uuid.UUID{vanishing.ID, appearing.ID}},
+		{crawledAt, []uuid.UUID{appearing.ID}},
+	}
+	for _, tc := range tests {
+		var got []uuid.UUID
+		for _, edge := range collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Time{}, linkgraph.AsOf(tc.asOf)) {
+			got = append(got, edge.ID)
+			if edge.ID == vanishing.ID && !edge.VanishedAt.Equal(crawledAt) {
+				t.Fatalf("\ngot: %v\nexpect: %v\nmessage: wrong vanish time", edge.VanishedAt, crawledAt)
+			}
+			if edge.ID == appearing.ID && !edge.VanishedAt.IsZero() {
+				t.Fatalf("live edge has vanish time %v", edge.VanishedAt)
+			}
+		}
+		assertSameIDs(t, got, tc.expected)
+	}
+
+	// current graph is not affected by the history
+	var got []uuid.UUID
+	for _, edge := range collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour)) {
+		got = append(got, edge.ID)
+	}
+	assertSameIDs(t, got, []uuid.UUID{appearing.ID})
+}
+
+func testUpdateScores(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 3)
+
+	scores := map[uuid.UUID]float64{ids[0]: 0.5, ids[1]: 0.25, uuid.New(): 1}
+	if err := g.UpdateScores(ctx, linkgraph.ScorePageRank, scores); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		link, err := g.LookupLink(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if link.PageRank != scores[id] {
+			t.Fatalf("\ngot: %v\nexpect: %v", link.PageRank, scores[id])
+		}
+	}
+
+	// upsert keep the stored scores and ignore the supplied ones
+	link := &linkgraph.Link{URL: "https://example.com/0", RetrievedAt: time.Now(), PageRank: 0.75}
+	if err := g.UpsertLink(ctx, link); err != nil {
+		t.Fatal(err)
+	}
+	if link.PageRank != 0.5 {
+		t.Fatalf("\ngot: %v\nexpect: %v", link.PageRank, 0.5)
+	}
+	fresh := &linkgraph.Link{URL: "https://example.com/fresh", PageRank: 0.75}
+	if err := g.UpsertLink(ctx, fresh); err != nil {
+		t.Fatal(err)
+	}
+	if fresh.PageRank != 0 {
+		t.Fatalf("\ngot: %v\nexpect: %v", fresh.PageRank, 0)
+	}
+
+	// the scores are streamed with the links
+	for _, l := range collectLinks(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour)) {
+		if l.PageRank != scores[l.ID] {
+			t.Fatalf("%v\ngot: %v\nexpect: %v", l.ID, l.PageRank, scores[l.ID])
+		}
+	}
+
+	if err := g.UpdateScores(ctx, linkgraph.ScoreKind(0), scores); !errors.Is(err, linkgraph.ErrUnknownScoreKind) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrUnknownScoreKind)
+	}
+
+	// every kind is stored on its own
+	kinds := []linkgraph.ScoreKind{linkgraph.ScorePageRank, linkgraph.ScoreTrustRank, linkgraph.ScoreHub, linkgraph.ScoreAuthority}
+	for _, kind := range kinds {
+		if err := g.UpdateScores(ctx, kind, map[uuid.UUID]float64{ids[2]: float64(kind)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, l := range collectLinks(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour)) {
+		if l.ID != ids[2] {
+			continue
+		}
+		for _, kind := range kinds {
+			if l.Score(kind) != float64(kind) {
+				t.Fatalf("%s\ngot: %v\nexpect: %v", kind, l.Score(kind), float64(kind))
+			}
+		}
+	}
+}
+
+func testUpsertRedirect(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 3)
+
+	for _, r := range []*linkgraph.Redirect{
+		{Alias: ids[0], Target: uuid.New()},
+		{Alias: uuid.New(), Target: ids[0]},
+	} {
+		if err := g.UpsertRedirect(ctx, r); !errors.Is(err, linkgraph.ErrUnknownEdgeLinks) {
+			t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrUnknownEdgeLinks)
+		}
+	}
+	if err := g.UpsertRedirect(ctx, &linkgraph.Redirect{Alias: ids[0], Target: ids[0]}); !errors.Is(err, linkgraph.ErrRedirectLoop) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrRedirectLoop)
+	}
+
+	// zero observed time is set by the graph
+	r := &linkgraph.Redirect{Alias: ids[0], Target: ids[1], StatusCode: 301}
+	if err := g.UpsertRedirect(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if r.ObservedAt.IsZero() {
+		t.Fatal("redirect observedAt not set")
+	}
+
+	// newer observation replace the redirect of the alias
+	observedAt := r.ObservedAt.Add(time.Hour).Truncate(time.Microsecond)
+	newer := &linkgraph.Redirect{Alias: ids[0], Target: ids[2], StatusCode: 308, ObservedAt: observedAt}
+	if err := g.UpsertRedirect(ctx, newer); err != nil {
+		t.Fatal(err)
+	}
+
+	// older observation is ignored and the stored redirect is returned
+	older := &linkgraph.Redirect{Alias: ids[0], Target: ids[1], StatusCode: 302, ObservedAt: observedAt.Add(-time.Minute)}
+	if err := g.UpsertRedirect(ctx, older); err != nil {
+		t.Fatal(err)
+	}
+	if older.Target != ids[2] || older.StatusCode != 308 || !older.ObservedAt.Equal(observedAt) {
+		t.Fatalf("\ngot: %+v\nexpect: %+v", older, newer)
+	}
+
+	chain, err := g.ResolveRedirect(ctx, ids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 1 || chain[0].Target != ids[2] || chain[0].StatusCode != 308 {
+		t.Fatalf("\ngot: %+v\nexpect: [%+v]", chain, newer)
+	}
+}
+
+func testResolveRedirect(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 5)
+
+	// 0 -> 1 -> 2, 3 -> 4 -> 3
+	for _, pair := range [][2]int{{0, 1}, {1, 2}, {3, 4}, {4, 3}} {
+		if err := g.UpsertRedirect(ctx, &linkgraph.Redirect{Alias: ids[pair[0]], Target: ids[pair[1]], StatusCode: 301}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chain, err := g.ResolveRedirect(ctx, ids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 || chain[0].Alias != ids[0] || chain[0].Target != ids[1] || chain[1].Alias != ids[1] || chain[1].Target != ids[2] {
+		t.Fatalf("\ngot: %+v\nexpect: %v -> %v -> %v", chain, ids[0], ids[1], ids[2])
+	}
+
+	// final target and unknown link do not redirect
+	for _, id := range []uuid.UUID{ids[2], uuid.New()} {
+		if chain, err := g.ResolveRedirect(ctx, id); err != nil || len(chain) != 0 {
+			t.Fatalf("\ngot: %+v %v\nexpect: empty chain", chain, err)
+		}
+	}
+
+	if _, err := g.ResolveRedirect(ctx, ids[3]); !errors.Is(err, linkgraph.ErrRedirectLoop) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrRedirectLoop)
+	}
+
+	// redirects go with their links
+	if err := g.RemoveLink(ctx, ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	if chain, err := g.ResolveRedirect(ctx, ids[0]); err != nil || len(chain) != 0 {
+		t.Fatalf("\ngot: %+v %v\nexpect: empty chain", chain, err)
+	}
+}
+
+func testEdgesResolveRedirects(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 6)
+
+	// 0 -> 1 -> 2, 3 -> 4 -> 3
+	for _, pair := range [][2]int{{0, 1}, {1, 2}, {3, 4}, {4, 3}} {
+		if err := g.UpsertRedirect(ctx, &linkgraph.Redirect{Alias: ids[pair[0]], Target: ids[pair[1]], StatusCode: 301}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 5 link to the alias, to the target and into the loop
+	expect := map[uuid.UUID][2]uuid.UUID{}
+	for _, dst := range []int{0, 2, 3} {
+		edge := &linkgraph.Edge{Src: ids[5], Dst: ids[dst]}
+		if err := g.UpsertEdge(ctx, edge); err != nil {
+			t.Fatal(err)
+		}
+		expect[edge.ID] = [2]uuid.UUID{edge.Dst, uuid.Nil}
+	}
+
+	got := collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour), linkgraph.ResolveRedirects())
+	if len(got) != len(expect) {
+		t.Fatalf("\ngot: %d edges\nexpect: %d", len(got), len(expect))
+	}
+	for _, edge := range got {
+		want := expect[edge.ID]
+		if want[0] == ids[0] {
+			want = [2]uuid.UUID{ids[2], ids[0]}
+		}
+		if edge.Dst != want[0] || edge.DstAlias != want[1] {
+			t.Fatalf("\ngot: dst %v alias %v\nexpect: dst %v alias %v", edge.Dst, edge.DstAlias, want[0], want[1])
+		}
+	}
+
+	// without the option the edges are left as is
+	for _, edge := range collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour)) {
+		if edge.Dst != expect[edge.ID][0] || edge.DstAlias != uuid.Nil {
+			t.Fatalf("\ngot: dst %v alias %v\nexpect: dst %v", edge.Dst, edge.DstAlias, expect[edge.ID][0])
+		}
+	}
+}
+
+func testRemoveLink(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 3)
+
+	// edges from and to the removed link are removed with it
+	for _, edge := range []*linkgraph.Edge{
+		{Src: ids[0], Dst: ids[1]},
+		{Src: ids[1], Dst: ids[0]},
+	} {
+		if err := g.UpsertEdge(ctx, edge); err != nil {
+			t.Fatal(err)
+		}
+	}
+	kept := &linkgraph.Edge{Src: ids[1], Dst: ids[2]}
+	if err := g.UpsertEdge(ctx, kept); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.RemoveLink(ctx, ids[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.LookupLink(ctx, ids[0]); !errors.Is(err, linkgraph.ErrNotFound) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+	if _, err := g.LookupLinkByURL(ctx, "https://example.com/0"); !errors.Is(err, linkgraph.ErrNotFound) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+
+	var got []uuid.UUID
+	for _, edge := range collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour)) {
+		got = append(got, edge.ID)
+	}
+	assertSameIDs(t, got, []uuid.UUID{kept.ID})
+
+	if err := g.RemoveLink(ctx, ids[0]); !errors.Is(err, linkgraph.ErrNotFound) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+}
+
+func testRemoveStaleLinks(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	now := time.Now().Truncate(time.Second).UTC()
+
+	links := []*linkgraph.Link{
+		{URL: "https://example.com/stale", RetrievedAt: now.Add(-2 * time.Hour)},
+		{URL: "https://example.com/fresh", RetrievedAt: now},
+		{URL: "https://example.com/pending"},
+	}
+	if err := g.UpsertLinks(ctx, links); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.UpsertEdge(ctx, &linkgraph.Edge{Src: links[1].ID, Dst: links[0].ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := g.RemoveStaleLinks(ctx, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", n, 1)
+	}
+
+	if _, err := g.LookupLink(ctx, links[0].ID); !errors.Is(err, linkgraph.ErrNotFound) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+	for _, link := range links[1:] {
+		if _, err := g.LookupLink(ctx, link.ID); err != nil {
+			t.Fatalf("\ngot: %v\nmessage: link %s should be kept", err, link.URL)
+		}
+	}
+	if n := len(collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))); n != 0 {
+		t.Fatalf("\ngot:\t %v edges, \nexpected:\t %v", n, 0)
+	}
+}
+
+func testRemoveOrphanLinks(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	now := time.Now().Truncate(time.Second).UTC()
+
+	links := []*linkgraph.Link{
+		{URL: "https://example.com/src", RetrievedAt: now.Add(-2 * time.Hour)},
+		{URL: "https://example.com/dst", RetrievedAt: now.Add(-2 * time.Hour)},
+		{URL: "https://example.com/orphan", RetrievedAt: now.Add(-2 * time.Hour)},
+		{URL: "https://example.com/young-orphan", RetrievedAt: now},
+		{URL: "https://example.com/pending-orphan"},
+	}
+	if err := g.UpsertLinks(ctx, links); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.UpsertEdge(ctx, &linkgraph.Edge{Src: links[0].ID, Dst: links[1].ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := g.RemoveOrphanLinks(ctx, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", n, 1)
+	}
+
+	if _, err := g.LookupLink(ctx, links[2].ID); !errors.Is(err, linkgraph.ErrNotFound) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+	if n := len(collectLinks(ctx, t, g, uuid.Nil, maxUUID, now.Add(time.Hour))); n != 4 {
+		t.Fatalf("\ngot:\t %v links, \nexpected:\t %v", n, 4)
+	}
+}
+
+func testCancelledContext(ctx context.Context, t *testing.T, g linkgraph.Graph) {
+	ids := createLinks(ctx, t, g, 2)
+	if err := g.UpsertEdge(ctx, &linkgraph.Edge{Src: ids[0], Dst: ids[1]}); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if err := g.UpsertLink(cancelled, &linkgraph.Link{URL: "https://example.com/cancelled"}); err == nil {
+		t.Fatal("expected upsert link with cancelled context to fail")
+	}
+	if err := g.UpsertEdge(cancelled, &linkgraph.Edge{Src: ids[1], Dst: ids[0]}); err == nil {
+		t.Fatal("expected upsert edge with cancelled context to fail")
+	}
+	if _, err := g.RemoveStaleEdges(cancelled, ids[0], time.Now().Add(time.Hour)); err == nil {
+		t.Fatal("expected remove stale edges with cancelled context to fail")
+	}
+
+	// iterator either fail on creation or report the error once advanced
+	linkIt, err := g.Links(cancelled, uuid.Nil, maxUUID, time.Now().Add(time.Hour))
+	if err == nil {
+		for linkIt.Next() {
+		}
+		err = linkIt.Error()
+		_ = linkIt.Close()
+	}
+	if err == nil {
+		t.Fatal("expected link iterator with cancelled context to fail")
+	}
+
+	edgeIt, err := g.Edges(cancelled, uuid.Nil, maxUUID, time.Now().Add(time.Hour))
+	if err == nil {
+		for edgeIt.Next() {
+		}
+		err = edgeIt.Error()
+		_ = edgeIt.Close()
+	}
+	if err == nil {
+		t.Fatal("expected edge iterator with cancelled context to fail")
+	}
+
+	// nothing must be changed by the cancelled calls
+	if n := len(collectLinks(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))); n != 2 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", n, 2)
+	}
+	if n := len(collectEdges(ctx, t, g, uuid.Nil, maxUUID, time.Now().Add(time.Hour))); n != 1 {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", n, 1)
+	}
+}
+
+//=========== helper
+
+func createLinks(ctx context.Context, t *testing.T, g linkgraph.Graph, n int) []uuid.UUID {
+	t.Helper()
+
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		link := &linkgraph.Link{URL: fmt.Sprintf("https://example.com/%d", i), RetrievedAt: time.Now()}
+		if err := g.UpsertLink(ctx, link); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = link.ID
+	}
+	return ids
+}
+
+func collectLinks(ctx context.Context, t *testing.T, g linkgraph.Graph, from, to uuid.UUID, retrieveBefore time.Time) []*linkgraph.Link {
+	t.Helper()
+
+	it, err := g.Links(ctx, from, to, retrieveBefore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var links []*linkgraph.Link
+	for it.Next() {
+		links = append(links, it.Link())
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return links
+}
+
+func collectEdges(ctx context.Context, t *testing.T, g linkgraph.Graph, from, to uuid.UUID, updateBefore time.Time, opts ...linkgraph.EdgeOption) []*linkgraph.Edge {
+	t.Helper()
+
+	it, err := g.Edges(ctx, from, to, updateBefore, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var edges []*linkgraph.Edge
+	for it.Next() {
+		edges = append(edges, it.Edge())
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return edges
+}
+
+func assertSameIDs(t *testing.T, got, expected []uuid.UUID) {
+	t.Helper()
+
+	got = append([]uuid.UUID(nil), got...)
+	expected = append([]uuid.UUID(nil), expected...)
+	sort.Slice(got, func(l, r int) bool { return got[l].String() < got[r].String() })
+	sort.Slice(expected, func(l, r int) bool { return expected[l].String() < expected[r].String() })
+
+	if len(got) != len(expected) {
+		t.Fatalf("\ngot:\t %v, \nexpected:\t %v", got, expected)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("\ngot:\t %v, \nexpected:\t %v", got, expected)
+		}
+	}
+}
+
+func waitIterators(t *testing.T, wg *sync.WaitGroup, errC <-chan error) {
+	t.Helper()
+
+	doneCh := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(doneCh)
+	}()
+
+	select {
+	case <-doneCh:
+		// every iterator may have reported an error right before finishing
+		select {
+		case err := <-errC:
+			t.Fatal(err)
+		default:
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for test to complete")
+	case err := <-errC:
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,15 @@
+package linkgraph
+
+import "github.com/google/uuid"
+
+// NameBasedID return the UUIDv5 of canonicalURL under namespace.
+//
+// graph configured with name-based IDs assign it to links instead of random
+// ID, so crawler that share the namespace and the url normalizer can compute
+// the ID of a link without asking the graph, and upsert edges before the
+// links they reference. edge that reference unknown link create a
+// placeholder link: it has no url and was never retrieved until the link is
+// upserted.
+func NameBasedID(namespace uuid.UUID, canonicalURL string) uuid.UUID {
+	return uuid.NewSHA1(namespace, []byte(canonicalURL))
+}
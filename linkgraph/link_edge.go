@@ -15,8 +15,49 @@ type Link struct {
 	// link target
 	URL string `db:"url"`
 
+	// url the link was first upserted with before normalization, empty when
+	// the url was not normalized. see Normalizer.
+	OriginalURL string `db:"original_url"`
+
 	// timestamp when link retrieved after processed
 	RetrievedAt time.Time `db:"retrieved_at"`
+
+	// metadata of the last retrieval, it is replaced only by upsert that
+	// carry RetrievedAt at least as recent as the stored one, so link that
+	// is merely discovered or an older replay keep the metadata.
+
+	// HTTP status code of the response
+	StatusCode int `db:"status_code"`
+
+	// Content-Type and Content-Length of the response
+	ContentType   string `db:"content_type"`
+	ContentLength int64  `db:"content_length"`
+
+	// hash of the response body used to detect change between retrieval
+	ContentHash []byte `db:"content_hash"`
+
+	// title of the page
+	Title string `db:"title"`
+
+	// Last-Modified and ETag header of the response, used for conditional
+	// request
+	LastModified time.Time `db:"last_modified"`
+	ETag         string    `db:"etag"`
+
+	// scores computed over the graph, they are only written by UpdateScores
+	// and zero until computed. see ScoreKind.
+
+	// PageRank of the link, the scores of every link sum to 1
+	PageRank float64 `db:"pagerank"`
+
+	// PageRank personalized to a set of trusted links, link that can't be
+	// reached from them has no trust
+	TrustRank float64 `db:"trustrank"`
+
+	// HITS scores of the link: a good hub link to many good authorities and
+	// a good authority is linked from many good hubs
+	HubScore       float64 `db:"hub"`
+	AuthorityScore float64 `db:"authority"`
 }
 
 // Edge represents a uni-directional connection between two links in the graph.
@@ -29,6 +70,47 @@ type Edge struct {
 
 	Dst uuid.UUID // Link ID
 
-	// timestamp when link is update
+	// timestamp when link is update, it is the last time the edge was seen
 	UpdateAt time.Time `db:"update_at"`
+
+	// first time the edge was seen, it is set by the graph and never move
+	// forward
+	FirstSeen time.Time `db:"first_seen"`
+
+	// time the edge was found missing from its source by RemoveStaleEdges,
+	// it is only set on edges returned from the history by AsOf.
+	VanishedAt time.Time `db:"vanished_at"`
+
+	// destination the edge was upserted with when Dst is rewritten to the
+	// final target of its redirects by ResolveRedirects, uuid.Nil otherwise.
+	DstAlias uuid.UUID `db:"-"`
+
+	// attributes of the link as seen on the source page, they are replaced
+	// on every upsert that is at least as recent as the stored edge.
+
+	// text of the anchor element
+	AnchorText string `db:"anchor_text"`
+
+	// rel attribute of the anchor element
+	Rel EdgeRel `db:"rel"`
+
+	// the link is part of navigation or other boilerplate of the page
+	Boilerplate bool `db:"boilerplate"`
+
+	// number of time the link occur on the source page
+	Occurrences int `db:"occurrences"`
+}
+
+// EdgeRel is the set of rel attribute values that matter for ranking.
+type EdgeRel uint8
+
+const (
+	RelNofollow EdgeRel = 1 << iota
+	RelSponsored
+	RelUGC
+)
+
+// Has report whether every flag of rel is set.
+func (r EdgeRel) Has(rel EdgeRel) bool {
+	return r&rel == rel
 }
@@ -0,0 +1,771 @@
+// Package memory provides an in-process, thread-safe implementation of
+// linkgraph.Graph. It follows the same semantics as the postgres backend and
+// is intended for tests and small deployments that do not need durability.
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+var _ linkgraph.Graph = (*Graph)(nil)
+
+// edgeList contains the ID of edges that originate from the same link.
+type edgeList []uuid.UUID
+
+// Graph never block on I/O, the context is only checked before doing any work
+// so a cancelled caller observe the same error as with other backends.
+type Graph struct {
+	mu sync.RWMutex
+
+	links map[uuid.UUID]*linkgraph.Link
+	edges map[uuid.UUID]*linkgraph.Edge
+
+	// linkURLIndex enforce url uniqueness of link
+	linkURLIndex map[string]*linkgraph.Link
+
+	// linkEdgeMap map link ID to the edges that originate from it
+	linkEdgeMap map[uuid.UUID]edgeList
+
+	// history hold the edges removed by RemoveStaleEdges, the in-memory
+	// graph always keep the edge history.
+	history []*linkgraph.Edge
+
+	// linkID derive the ID of link from its url, nil for random ID
+	linkID func(url string) uuid.UUID
+
+	// redirects map alias link ID to its redirect
+	redirects map[uuid.UUID]*linkgraph.Redirect
+
+	// store clock and where link retrieved_at and edge update_at come from,
+	// like the postgres graph
+	clock    func() time.Time
+	linkTime linkgraph.TimeSource
+	edgeTime linkgraph.TimeSource
+}
+
+// Option configure the in-memory graph.
+type Option func(*Graph)
+
+// WithNameBasedIDs assign link ID derived from the url under namespace, see
+// linkgraph.NameBasedID.
+func WithNameBasedIDs(namespace uuid.UUID) Option {
+	return func(g *Graph) {
+		g.linkID = func(url string) uuid.UUID {
+			return linkgraph.NameBasedID(namespace, url)
+		}
+	}
+}
+
+// WithClock set the store clock, it default to time.Now.
+func WithClock(clock func() time.Time) Option {
+	return func(g *Graph) {
+		g.clock = clock
+	}
+}
+
+// WithTimeSource select where link retrieved_at come from, it default to
+// linkgraph.CallerTime.
+func WithTimeSource(src linkgraph.TimeSource) Option {
+	return func(g *Graph) {
+		g.linkTime = src
+	}
+}
+
+// WithEdgeTimeSource select where edge update_at come from, it default to
+// linkgraph.StoreTime. edge with zero UpdateAt is always stamped by the store
+// clock and update_at never move backward.
+func WithEdgeTimeSource(src linkgraph.TimeSource) Option {
+	return func(g *Graph) {
+		g.edgeTime = src
+	}
+}
+
+// New returns an empty in-memory graph.
+func New(opts ...Option) *Graph {
+	g := Graph{
+		links:        make(map[uuid.UUID]*linkgraph.Link),
+		edges:        make(map[uuid.UUID]*linkgraph.Edge),
+		linkURLIndex: make(map[string]*linkgraph.Link),
+		linkEdgeMap:  make(map[uuid.UUID]edgeList),
+		redirects:    make(map[uuid.UUID]*linkgraph.Redirect),
+		clock:        time.Now,
+		linkTime:     linkgraph.CallerTime,
+		edgeTime:     linkgraph.StoreTime,
+	}
+	for _, opt := range opts {
+		opt(&g)
+	}
+	return &g
+}
+
+// UpsertLink implements linkgraph.Graph.
+func (g *Graph) UpsertLink(ctx context.Context, link *linkgraph.Link) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.upsertLink(link)
+}
+
+// UpsertLinks implements linkgraph.Graph.
+func (g *Graph) UpsertLinks(ctx context.Context, links []*linkgraph.Link) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	errs := make([]error, len(links))
+	for i, link := range links {
+		errs[i] = g.upsertLink(link)
+	}
+
+	// links sharing the same url must reflect the final state of the batch
+	for i, link := range links {
+		if errs[i] == nil {
+			*link = *g.linkURLIndex[link.URL]
+		}
+	}
+	return linkgraph.NewBatchError(errs)
+}
+
+// upsertLink expect the caller to hold the write lock.
+func (g *Graph) upsertLink(link *linkgraph.Link) error {
+	var id uuid.UUID
+	if g.linkID != nil {
+		if id = g.linkID(link.URL); link.ID != uuid.Nil && link.ID != id {
+			return linkgraph.ErrLinkIDMismatch
+		}
+	}
+
+	link.RetrievedAt = g.linkRetrievedAt(link.RetrievedAt).UTC()
+	link.LastModified = link.LastModified.UTC()
+	link.ContentHash = cloneBytes(link.ContentHash)
+
+	// link with same url already exist, only move retrieved_at forward and
+	// take the metadata of a retrieval that is at least as recent. the
+	// original url is the one the link was first upserted with.
+	if existing := g.linkURLIndex[link.URL]; existing != nil {
+		if !link.RetrievedAt.IsZero() && !link.RetrievedAt.Before(existing.RetrievedAt) {
+			prev := *existing
+			*existing = *link
+			existing.ID = prev.ID
+			existing.CopyScores(&prev)
+			if prev.OriginalURL != "" {
+				existing.OriginalURL = prev.OriginalURL
+			}
+		} else if existing.OriginalURL == "" {
+			existing.OriginalURL = link.OriginalURL
+		}
+		*link = *existing
+		return nil
+	}
+
+	// assign new ID, the caller supplied ID is ignored like in postgres.
+	// name-based ID may belong to a placeholder that is replaced.
+	if g.linkID != nil {
+		link.ID = id
+	} else {
+		for {
+			link.ID = uuid.New()
+			if g.links[link.ID] == nil {
+				break
+			}
+		}
+	}
+
+	// scores are only written by UpdateScores, placeholder may already have
+	// them
+	lCopy := new(linkgraph.Link)
+	*lCopy = *link
+	lCopy.CopyScores(&linkgraph.Link{})
+	if placeholder := g.links[lCopy.ID]; placeholder != nil {
+		lCopy.CopyScores(placeholder)
+		*placeholder = *lCopy
+		lCopy = placeholder
+	}
+	link.CopyScores(lCopy)
+	g.links[lCopy.ID] = lCopy
+	g.linkURLIndex[lCopy.URL] = lCopy
+	return nil
+}
+
+// LookupLink implements linkgraph.Graph.
+func (g *Graph) LookupLink(ctx context.Context, id uuid.UUID) (*linkgraph.Link, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	link := g.links[id]
+	if link == nil {
+		return nil, linkgraph.ErrNotFound
+	}
+
+	lCopy := new(linkgraph.Link)
+	*lCopy = *link
+	return lCopy, nil
+}
+
+// LookupLinkByURL implements linkgraph.Graph.
+func (g *Graph) LookupLinkByURL(ctx context.Context, url string) (*linkgraph.Link, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	link := g.linkURLIndex[url]
+	if link == nil {
+		return nil, linkgraph.ErrNotFound
+	}
+
+	lCopy := new(linkgraph.Link)
+	*lCopy = *link
+	return lCopy, nil
+}
+
+// ResolveURLs implements linkgraph.Graph.
+func (g *Graph) ResolveURLs(ctx context.Context, urls []string) (map[string]uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	resolved := make(map[string]uuid.UUID, len(urls))
+	for _, url := range urls {
+		if link := g.linkURLIndex[url]; link != nil {
+			resolved[url] = link.ID
+		}
+	}
+	return resolved, nil
+}
+
+// Links implements linkgraph.Graph.
+func (g *Graph) Links(ctx context.Context, fromID, toID uuid.UUID, retrieveBefore time.Time) (linkgraph.LinkIterator, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	lastID, ok := linkgraph.Range{From: fromID, To: toID}.Last()
+	if !ok {
+		return &linkIterator{ctx: ctx}, nil
+	}
+	from, last := fromID.String(), lastID.String()
+
+	g.mu.RLock()
+	var list []*linkgraph.Link
+	for linkID, link := range g.links {
+		if id := linkID.String(); id >= from && id <= last && link.RetrievedAt.Before(retrieveBefore) {
+			lCopy := new(linkgraph.Link)
+			*lCopy = *link
+			list = append(list, lCopy)
+		}
+	}
+	g.mu.RUnlock()
+
+	// uuid string order is the same as byte order
+	sort.Slice(list, func(i, j int) bool { return list[i].ID.String() < list[j].ID.String() })
+
+	return &linkIterator{ctx: ctx, links: list}, nil
+}
+
+// UpsertEdge implements linkgraph.Graph.
+func (g *Graph) UpsertEdge(ctx context.Context, edge *linkgraph.Edge) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.upsertEdge(edge)
+}
+
+// UpsertEdges implements linkgraph.Graph.
+func (g *Graph) UpsertEdges(ctx context.Context, edges []*linkgraph.Edge) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	errs := make([]error, len(edges))
+	for i, edge := range edges {
+		errs[i] = g.upsertEdge(edge)
+	}
+
+	// edges sharing the same (src,dst) must reflect the final state of the batch
+	for i, edge := range edges {
+		if errs[i] == nil {
+			*edge = *g.edges[edge.ID]
+		}
+	}
+	return linkgraph.NewBatchError(errs)
+}
+
+// createPlaceholders create the unknown links with name-based ID, the caller
+// must hold the write lock.
+func (g *Graph) createPlaceholders(ids ...uuid.UUID) {
+	if g.linkID == nil {
+		return
+	}
+	for _, id := range ids {
+		if g.links[id] == nil {
+			g.links[id] = &linkgraph.Link{ID: id}
+		}
+	}
+}
+
+// upsertEdge expect the caller to hold the write lock.
+func (g *Graph) upsertEdge(edge *linkgraph.Edge) error {
+	g.createPlaceholders(edge.Src, edge.Dst)
+
+	_, srcExists := g.links[edge.Src]
+	_, dstExists := g.links[edge.Dst]
+	if !srcExists || !dstExists {
+		return linkgraph.ErrUnknownEdgeLinks
+	}
+
+	updateAt := g.edgeUpdateAt(edge.UpdateAt)
+
+	// edge with same (src,dst) already exist, update_at only move forward
+	// and first_seen only backward. the attributes are taken from the upsert
+	// unless it is older than the stored edge.
+	for _, edgeID := range g.linkEdgeMap[edge.Src] {
+		existing := g.edges[edgeID]
+		if existing.Dst == edge.Dst {
+			if !updateAt.Before(existing.UpdateAt) {
+				prev := *existing
+				*existing = *edge
+				existing.ID, existing.FirstSeen = prev.ID, prev.FirstSeen
+				existing.UpdateAt = updateAt
+			}
+			if updateAt.Before(existing.FirstSeen) {
+				existing.FirstSeen = updateAt
+			}
+			existing.VanishedAt = time.Time{}
+			*edge = *existing
+			return nil
+		}
+	}
+
+	for {
+		edge.ID = uuid.New()
+		if g.edges[edge.ID] == nil {
+			break
+		}
+	}
+	edge.UpdateAt = updateAt
+	edge.FirstSeen = updateAt
+	edge.VanishedAt = time.Time{}
+
+	eCopy := new(linkgraph.Edge)
+	*eCopy = *edge
+	g.edges[eCopy.ID] = eCopy
+	g.linkEdgeMap[eCopy.Src] = append(g.linkEdgeMap[eCopy.Src], eCopy.ID)
+	return nil
+}
+
+// LookupEdge implements linkgraph.Graph.
+func (g *Graph) LookupEdge(ctx context.Context, id uuid.UUID) (*linkgraph.Edge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	edge := g.edges[id]
+	if edge == nil {
+		return nil, linkgraph.ErrNotFound
+	}
+
+	eCopy := new(linkgraph.Edge)
+	*eCopy = *edge
+	return eCopy, nil
+}
+
+// Edges implements linkgraph.Graph.
+func (g *Graph) Edges(ctx context.Context, fromID, toID uuid.UUID, updateBefore time.Time, opts ...linkgraph.EdgeOption) (linkgraph.EdgeIterator, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	o := linkgraph.NewEdgeOptions(opts...)
+
+	lastID, ok := linkgraph.Range{From: fromID, To: toID}.Last()
+	if !ok {
+		return &edgeIterator{ctx: ctx}, nil
+	}
+	from, last := fromID.String(), lastID.String()
+
+	inRange := func(edge *linkgraph.Edge) bool {
+		id := edge.Src.String()
+		return id >= from && id <= last && o.Match(edge, updateBefore)
+	}
+
+	g.mu.RLock()
+	var list []*linkgraph.Edge
+	seen := make(map[[2]uuid.UUID]bool)
+	for linkID := range g.links {
+		for _, edgeID := range g.linkEdgeMap[linkID] {
+			if edge := g.edges[edgeID]; inRange(edge) {
+				eCopy := new(linkgraph.Edge)
+				*eCopy = *edge
+				list = append(list, eCopy)
+				seen[[2]uuid.UUID{edge.Src, edge.Dst}] = true
+			}
+		}
+	}
+
+	// like postgres, the live edge win over the history of the same pair
+	if !o.AsOf.IsZero() {
+		for _, edge := range g.history {
+			key := [2]uuid.UUID{edge.Src, edge.Dst}
+			if !seen[key] && inRange(edge) {
+				eCopy := new(linkgraph.Edge)
+				*eCopy = *edge
+				list = append(list, eCopy)
+				seen[key] = true
+			}
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if src1, src2 := list[i].Src.String(), list[j].Src.String(); src1 != src2 {
+			return src1 < src2
+		}
+		return list[i].Dst.String() < list[j].Dst.String()
+	})
+
+	// like postgres, edges keep the order of the alias
+	if o.ResolveRedirects {
+		for _, edge := range list {
+			if chain, err := linkgraph.FollowRedirects(edge.Dst, g.lookupRedirect); err == nil && len(chain) > 0 {
+				edge.DstAlias, edge.Dst = edge.Dst, chain[len(chain)-1].Target
+			}
+		}
+	}
+	g.mu.RUnlock()
+
+	return &edgeIterator{ctx: ctx, edges: list}, nil
+}
+
+// UpdateScores implements linkgraph.Graph.
+func (g *Graph) UpdateScores(ctx context.Context, kind linkgraph.ScoreKind, scores map[uuid.UUID]float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !kind.Valid() {
+		return linkgraph.ErrUnknownScoreKind
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for id, score := range scores {
+		if link := g.links[id]; link != nil {
+			link.SetScore(kind, score)
+		}
+	}
+	return nil
+}
+
+// UpsertRedirect implements linkgraph.Graph.
+func (g *Graph) UpsertRedirect(ctx context.Context, redirect *linkgraph.Redirect) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if redirect.Alias == redirect.Target {
+		return linkgraph.ErrRedirectLoop
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.createPlaceholders(redirect.Alias, redirect.Target)
+	if g.links[redirect.Alias] == nil || g.links[redirect.Target] == nil {
+		return linkgraph.ErrUnknownEdgeLinks
+	}
+
+	if redirect.ObservedAt.IsZero() {
+		redirect.ObservedAt = g.now()
+	}
+	redirect.ObservedAt = redirect.ObservedAt.UTC()
+
+	// older observation does not replace the stored redirect
+	if existing := g.redirects[redirect.Alias]; existing != nil && redirect.ObservedAt.Before(existing.ObservedAt) {
+		*redirect = *existing
+		return nil
+	}
+
+	rCopy := new(linkgraph.Redirect)
+	*rCopy = *redirect
+	g.redirects[rCopy.Alias] = rCopy
+	return nil
+}
+
+// ResolveRedirect implements linkgraph.Graph.
+func (g *Graph) ResolveRedirect(ctx context.Context, linkID uuid.UUID) ([]*linkgraph.Redirect, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	chain, err := linkgraph.FollowRedirects(linkID, g.lookupRedirect)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, r := range chain {
+		rCopy := new(linkgraph.Redirect)
+		*rCopy = *r
+		chain[i] = rCopy
+	}
+	return chain, nil
+}
+
+// lookupRedirect expect the caller to hold the lock.
+func (g *Graph) lookupRedirect(alias uuid.UUID) *linkgraph.Redirect {
+	return g.redirects[alias]
+}
+
+// OutEdges implements linkgraph.Graph.
+func (g *Graph) OutEdges(ctx context.Context, linkID uuid.UUID) (linkgraph.EdgeIterator, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.mu.RLock()
+	var list []*linkgraph.Edge
+	for _, edgeID := range g.linkEdgeMap[linkID] {
+		eCopy := new(linkgraph.Edge)
+		*eCopy = *g.edges[edgeID]
+		list = append(list, eCopy)
+	}
+	g.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Dst.String() < list[j].Dst.String() })
+
+	return &edgeIterator{ctx: ctx, edges: list}, nil
+}
+
+// InEdges implements linkgraph.Graph.
+func (g *Graph) InEdges(ctx context.Context, linkID uuid.UUID) (linkgraph.EdgeIterator, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// there is no reverse index, every edge is visited
+	g.mu.RLock()
+	var list []*linkgraph.Edge
+	for _, edge := range g.edges {
+		if edge.Dst == linkID {
+			eCopy := new(linkgraph.Edge)
+			*eCopy = *edge
+			list = append(list, eCopy)
+		}
+	}
+	g.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Src.String() < list[j].Src.String() })
+
+	return &edgeIterator{ctx: ctx, edges: list}, nil
+}
+
+// RemoveStaleEdges implements linkgraph.Graph.
+func (g *Graph) RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time, opts ...linkgraph.RemoveOption) (*linkgraph.RemovedEdges, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	o := linkgraph.NewRemoveOptions(opts...)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var (
+		removed     linkgraph.RemovedEdges
+		newEdgeList edgeList
+	)
+	for _, edgeID := range g.linkEdgeMap[fromID] {
+		edge := g.edges[edgeID]
+		if edge.UpdateAt.Before(updatedBefore) {
+			removed.Count++
+			removed.DstIDs = append(removed.DstIDs, edge.Dst)
+			if !o.DryRun {
+				delete(g.edges, edgeID)
+				edge.VanishedAt = updatedBefore.UTC()
+				g.history = append(g.history, edge)
+			}
+			continue
+		}
+
+		newEdgeList = append(newEdgeList, edgeID)
+	}
+
+	// Replace edge list or origin link with the filtered edge list
+	if !o.DryRun && removed.Count > 0 {
+		g.linkEdgeMap[fromID] = newEdgeList
+	}
+	return &removed, nil
+}
+
+// RemoveLink implements linkgraph.Graph.
+func (g *Graph) RemoveLink(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.links[id]; !ok {
+		return linkgraph.ErrNotFound
+	}
+
+	g.removeLinks(map[uuid.UUID]bool{id: true})
+	return nil
+}
+
+// RemoveStaleLinks implements linkgraph.Graph.
+func (g *Graph) RemoveStaleLinks(ctx context.Context, retrievedBefore time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stale := make(map[uuid.UUID]bool)
+	for id, link := range g.links {
+		if !link.RetrievedAt.IsZero() && link.RetrievedAt.Before(retrievedBefore) {
+			stale[id] = true
+		}
+	}
+
+	g.removeLinks(stale)
+	return int64(len(stale)), nil
+}
+
+// RemoveOrphanLinks implements linkgraph.Graph.
+func (g *Graph) RemoveOrphanLinks(ctx context.Context, retrievedBefore time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	connected := make(map[uuid.UUID]bool)
+	for _, edge := range g.edges {
+		connected[edge.Src] = true
+		connected[edge.Dst] = true
+	}
+
+	orphans := make(map[uuid.UUID]bool)
+	for id, link := range g.links {
+		if !connected[id] && !link.RetrievedAt.IsZero() && link.RetrievedAt.Before(retrievedBefore) {
+			orphans[id] = true
+		}
+	}
+
+	g.removeLinks(orphans)
+	return int64(len(orphans)), nil
+}
+
+// removeLinks remove the links and every edge that reference them, the
+// caller must hold the write lock.
+func (g *Graph) removeLinks(ids map[uuid.UUID]bool) {
+	if len(ids) == 0 {
+		return
+	}
+
+	for id := range ids {
+		// placeholder is not indexed
+		if link := g.links[id]; g.linkURLIndex[link.URL] == link {
+			delete(g.linkURLIndex, link.URL)
+		}
+		delete(g.links, id)
+
+		for _, edgeID := range g.linkEdgeMap[id] {
+			delete(g.edges, edgeID)
+		}
+		delete(g.linkEdgeMap, id)
+	}
+
+	// incoming edges of removed links
+	for src, list := range g.linkEdgeMap {
+		var newEdgeList edgeList
+		for _, edgeID := range list {
+			if ids[g.edges[edgeID].Dst] {
+				delete(g.edges, edgeID)
+				continue
+			}
+			newEdgeList = append(newEdgeList, edgeID)
+		}
+		g.linkEdgeMap[src] = newEdgeList
+	}
+
+	for alias, r := range g.redirects {
+		if ids[alias] || ids[r.Target] {
+			delete(g.redirects, alias)
+		}
+	}
+
+	// the history of removed links goes with them
+	var history []*linkgraph.Edge
+	for _, edge := range g.history {
+		if !ids[edge.Src] && !ids[edge.Dst] {
+			history = append(history, edge)
+		}
+	}
+	g.history = history
+}
+
+// cloneBytes copy b so the stored link does not share memory with caller,
+// empty slice is stored as nil like in postgres.
+func cloneBytes(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
+
+// now return the store clock in UTC.
+func (g *Graph) now() time.Time {
+	return g.clock().UTC()
+}
+
+// linkRetrievedAt apply the time source to timestamp supplied by caller.
+func (g *Graph) linkRetrievedAt(retrievedAt time.Time) time.Time {
+	if g.linkTime == linkgraph.StoreTime {
+		return g.now()
+	}
+	return retrievedAt
+}
+
+// edgeUpdateAt apply the edge time source to timestamp supplied by caller.
+func (g *Graph) edgeUpdateAt(updateAt time.Time) time.Time {
+	if g.edgeTime == linkgraph.CallerTime && !updateAt.IsZero() {
+		return updateAt.UTC()
+	}
+	return g.now()
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/linkgraph"
+	"github.com/odit-bit/linkstore/linkgraph/graphtest"
+)
+
+func Test_memory(t *testing.T) {
+	graphtest.Run(t, func(t *testing.T) linkgraph.Graph {
+		return New()
+	})
+}
+
+func Test_memory_name_based_ids(t *testing.T) {
+	namespace := uuid.New()
+	graphtest.RunNameBasedIDs(t, namespace, func(t *testing.T) linkgraph.Graph {
+		return New(WithNameBasedIDs(namespace))
+	})
+}
+
+func Test_memory_caller_edge_time(t *testing.T) {
+	graphtest.RunCallerEdgeTime(t, func(t *testing.T) linkgraph.Graph {
+		return New(WithEdgeTimeSource(linkgraph.CallerTime))
+	})
+}
+
+func Test_memory_clock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	g := New(WithClock(func() time.Time { return now }), WithTimeSource(linkgraph.StoreTime))
+
+	links := []*linkgraph.Link{
+		{URL: "https://example.com/a", RetrievedAt: time.Now()},
+		{URL: "https://example.com/b"},
+	}
+	if err := g.UpsertLinks(ctx, links); err != nil {
+		t.Fatal(err)
+	}
+	edge := &linkgraph.Edge{Src: links[0].ID, Dst: links[1].ID, UpdateAt: time.Now()}
+	if err := g.UpsertEdge(ctx, edge); err != nil {
+		t.Fatal(err)
+	}
+	redirect := &linkgraph.Redirect{Alias: links[1].ID, Target: links[0].ID, StatusCode: 301}
+	if err := g.UpsertRedirect(ctx, redirect); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, got := range []time.Time{links[0].RetrievedAt, links[1].RetrievedAt, edge.UpdateAt, edge.FirstSeen, redirect.ObservedAt} {
+		if !got.Equal(now) {
+			t.Fatalf("\ngot: %v\nexpect: %v", got, now)
+		}
+	}
+}
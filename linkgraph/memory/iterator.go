@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+var _ linkgraph.LinkIterator = (*linkIterator)(nil)
+
+// linkIterator iterate over snapshot of links taken when iterator created.
+type linkIterator struct {
+	ctx   context.Context
+	err   error
+	links []*linkgraph.Link
+	curr  int
+}
+
+// Close implements linkgraph.LinkIterator.
+func (it *linkIterator) Close() error {
+	return nil
+}
+
+// Error implements linkgraph.LinkIterator.
+func (it *linkIterator) Error() error {
+	return it.err
+}
+
+// Link implements linkgraph.LinkIterator.
+func (it *linkIterator) Link() *linkgraph.Link {
+	return it.links[it.curr-1]
+}
+
+// Next implements linkgraph.LinkIterator.
+func (it *linkIterator) Next() bool {
+	if it.err = it.ctx.Err(); it.err != nil {
+		return false
+	}
+	if it.curr >= len(it.links) {
+		return false
+	}
+	it.curr++
+	return true
+}
+
+var _ linkgraph.EdgeIterator = (*edgeIterator)(nil)
+
+// edgeIterator iterate over snapshot of edges taken when iterator created.
+type edgeIterator struct {
+	ctx   context.Context
+	err   error
+	edges []*linkgraph.Edge
+	curr  int
+}
+
+// Close implements linkgraph.EdgeIterator.
+func (it *edgeIterator) Close() error {
+	return nil
+}
+
+// Edge implements linkgraph.EdgeIterator.
+func (it *edgeIterator) Edge() *linkgraph.Edge {
+	return it.edges[it.curr-1]
+}
+
+// Error implements linkgraph.EdgeIterator.
+func (it *edgeIterator) Error() error {
+	return it.err
+}
+
+// Next implements linkgraph.EdgeIterator.
+func (it *edgeIterator) Next() bool {
+	if it.err = it.ctx.Err(); it.err != nil {
+		return false
+	}
+	if it.curr >= len(it.edges) {
+		return false
+	}
+	it.curr++
+	return true
+}
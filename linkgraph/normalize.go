@@ -0,0 +1,236 @@
+package linkgraph
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// TrackingParams is the list of common tracking query parameters, name that
+// end with '*' match every parameter with that prefix.
+var TrackingParams = []string{
+	"utm_*",
+	"gclid",
+	"dclid",
+	"fbclid",
+	"msclkid",
+	"yclid",
+	"mc_cid",
+	"mc_eid",
+	"_ga",
+}
+
+// defaultPorts are removed from the host.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Normalizer rewrite url into canonical form so the different spelling of the
+// same page end up as a single link. the zero options only apply rewrite that
+// never change the page being addressed:
+//
+//   - scheme and host are lowercased, internationalized host is encoded
+//     as punycode
+//   - default port is removed
+//   - empty path become "/"
+//   - fragment is removed
+//   - query parameters are sorted by name, parameters of the same name keep
+//     their order
+//
+// a nil *Normalizer leave url untouched.
+type Normalizer struct {
+	params   map[string]bool
+	prefixes []string
+
+	mergeSchemes       bool
+	stripTrailingSlash bool
+}
+
+// NormalizeOption configure Normalizer.
+type NormalizeOption func(*Normalizer)
+
+// NewNormalizer return normalizer configured by opts.
+func NewNormalizer(opts ...NormalizeOption) *Normalizer {
+	n := Normalizer{params: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&n)
+	}
+	return &n
+}
+
+// StripParams remove the query parameters with given names, e.g.
+// TrackingParams. name that end with '*' is a prefix.
+func StripParams(names ...string) NormalizeOption {
+	return func(n *Normalizer) {
+		for _, name := range names {
+			if prefix, ok := strings.CutSuffix(name, "*"); ok {
+				n.prefixes = append(n.prefixes, prefix)
+				continue
+			}
+			n.params[name] = true
+		}
+	}
+}
+
+// MergeSchemes rewrite http url to https, for crawl that consider both to be
+// the same page.
+func MergeSchemes() NormalizeOption {
+	return func(n *Normalizer) {
+		n.mergeSchemes = true
+	}
+}
+
+// StripTrailingSlash remove trailing slash of the path, except the root path.
+func StripTrailingSlash() NormalizeOption {
+	return func(n *Normalizer) {
+		n.stripTrailingSlash = true
+	}
+}
+
+// Normalize return the canonical form of rawURL. url that is not absolute
+// is rejected with ErrInvalidURL. normalizing an already normalized url
+// return it unchanged.
+func (n *Normalizer) Normalize(rawURL string) (string, error) {
+	if n == nil {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("%w: %q is not absolute", ErrInvalidURL, rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if n.mergeSchemes && u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if port := u.Port(); port != "" && port != defaultPorts[u.Scheme] {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		// ipv6 literal
+		host = "[" + host + "]"
+	}
+	u.Host = host
+
+	if u.Path == "" {
+		u.Path, u.RawPath = "/", ""
+	}
+	if n.stripTrailingSlash && len(u.Path) > 1 && strings.HasSuffix(u.Path, "/") {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+		if u.Path == "" {
+			u.Path, u.RawPath = "/", ""
+		}
+	}
+
+	u.RawQuery = n.normalizeQuery(u.RawQuery)
+	u.ForceQuery = false
+	u.Fragment, u.RawFragment = "", ""
+
+	return u.String(), nil
+}
+
+// NormalizeLink normalize the url of link, the url supplied by the caller is
+// kept as OriginalURL when normalization changed it, unless OriginalURL is
+// already set.
+func (n *Normalizer) NormalizeLink(link *Link) error {
+	if n == nil {
+		return nil
+	}
+
+	normalized, err := n.Normalize(link.URL)
+	if err != nil {
+		return err
+	}
+
+	if link.OriginalURL == "" && normalized != link.URL {
+		link.OriginalURL = link.URL
+	}
+	link.URL = normalized
+	return nil
+}
+
+// NormalizeAll normalize urls and return every normalized url with the urls
+// of the input that normalize to it, url that can't be normalized is skipped.
+func (n *Normalizer) NormalizeAll(urls []string) map[string][]string {
+	normalized := make(map[string][]string, len(urls))
+	for _, rawURL := range urls {
+		if u, err := n.Normalize(rawURL); err == nil {
+			normalized[u] = append(normalized[u], rawURL)
+		}
+	}
+	return normalized
+}
+
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return "", fmt.Errorf("empty host")
+	}
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+	return idna.Punycode.ToASCII(host)
+}
+
+// normalizeQuery sort the parameters of query by name and drop the stripped
+// ones, the encoding of the remaining parameters is kept as is.
+func (n *Normalizer) normalizeQuery(query string) string {
+	if query == "" {
+		return ""
+	}
+
+	type param struct {
+		name string
+		raw  string
+	}
+
+	var params []param
+	for _, raw := range strings.Split(query, "&") {
+		if raw == "" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(raw, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if n.stripped(name) {
+			continue
+		}
+		params = append(params, param{name: name, raw: raw})
+	}
+
+	sort.SliceStable(params, func(i, j int) bool { return params[i].name < params[j].name })
+
+	raws := make([]string, len(params))
+	for i, p := range params {
+		raws[i] = p.raw
+	}
+	return strings.Join(raws, "&")
+}
+
+func (n *Normalizer) stripped(name string) bool {
+	if n.params[name] {
+		return true
+	}
+	for _, prefix := range n.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
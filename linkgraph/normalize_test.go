@@ -0,0 +1,122 @@
+package linkgraph
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_normalizer(t *testing.T) {
+	t.Run("default rewrite", test_normalize_default)
+	t.Run("optional rewrite", test_normalize_options)
+	t.Run("invalid url", test_normalize_invalid)
+	t.Run("normalize link", test_normalize_link)
+}
+
+func test_normalize_default(t *testing.T) {
+	n := NewNormalizer()
+
+	tests := []struct {
+		raw    string
+		expect string
+	}{
+		{"HTTP://Example.COM/a/", "http://example.com/a/"},
+		{"https://example.com", "https://example.com/"},
+		{"https://example.com:443/a", "https://example.com/a"},
+		{"http://example.com:80/a", "http://example.com/a"},
+		{"http://example.com:8080/a", "http://example.com:8080/a"},
+		{"https://example.com/a#section", "https://example.com/a"},
+		{"https://example.com/a?b=2&a=1&b=1", "https://example.com/a?a=1&b=2&b=1"},
+		{"https://example.com/a?", "https://example.com/a"},
+		{"https://example.com./a", "https://example.com/a"},
+		{"https://bücher.example/a", "https://xn--bcher-kva.example/a"},
+		{"https://[::1]:443/a", "https://[::1]/a"},
+		{"https://example.com/a%2Fb?q=a%20b", "https://example.com/a%2Fb?q=a%20b"},
+	}
+
+	for _, tc := range tests {
+		got, err := n.Normalize(tc.raw)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.raw, err)
+		}
+		if got != tc.expect {
+			t.Fatalf("\ngot: %s\nexpect: %s", got, tc.expect)
+		}
+
+		// normalized url is a fixed point
+		again, err := n.Normalize(got)
+		if err != nil || again != got {
+			t.Fatalf("\ngot: %s %v\nexpect: %s", again, err, got)
+		}
+	}
+}
+
+func test_normalize_options(t *testing.T) {
+	n := NewNormalizer(StripParams(TrackingParams...), MergeSchemes(), StripTrailingSlash())
+
+	tests := []struct {
+		raw    string
+		expect string
+	}{
+		{"http://Example.com/a/", "https://example.com/a"},
+		{"https://example.com/a", "https://example.com/a"},
+		{"http://example.com/", "https://example.com/"},
+		{"https://example.com/a?utm_source=x&id=1&utm_medium=y&fbclid=z", "https://example.com/a?id=1"},
+		{"https://example.com/a?utm_source=x", "https://example.com/a"},
+		{"ftp://example.com/a/", "ftp://example.com/a"},
+	}
+
+	for _, tc := range tests {
+		got, err := n.Normalize(tc.raw)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.raw, err)
+		}
+		if got != tc.expect {
+			t.Fatalf("\ngot: %s\nexpect: %s", got, tc.expect)
+		}
+	}
+}
+
+func test_normalize_invalid(t *testing.T) {
+	n := NewNormalizer()
+	for _, raw := range []string{"", "/relative/path", "example.com/a", "https://exa mple.com/", "http://[::1/"} {
+		if _, err := n.Normalize(raw); !errors.Is(err, ErrInvalidURL) {
+			t.Fatalf("%q\ngot: %v\nexpect: %v", raw, err, ErrInvalidURL)
+		}
+	}
+
+	// nil normalizer accept anything
+	var nilNormalizer *Normalizer
+	if got, err := nilNormalizer.Normalize("example.com/a"); err != nil || got != "example.com/a" {
+		t.Fatalf("\ngot: %s %v\nexpect: %s", got, err, "example.com/a")
+	}
+}
+
+func test_normalize_link(t *testing.T) {
+	n := NewNormalizer()
+
+	link := Link{URL: "https://Example.com/a#top"}
+	if err := n.NormalizeLink(&link); err != nil {
+		t.Fatal(err)
+	}
+	if link.URL != "https://example.com/a" || link.OriginalURL != "https://Example.com/a#top" {
+		t.Fatalf("\ngot: %+v", link)
+	}
+
+	// original url supplied by caller is kept
+	link = Link{URL: "https://Example.com/a", OriginalURL: "http://example.com/a?utm_source=x"}
+	if err := n.NormalizeLink(&link); err != nil {
+		t.Fatal(err)
+	}
+	if link.OriginalURL != "http://example.com/a?utm_source=x" {
+		t.Fatalf("\ngot: %s", link.OriginalURL)
+	}
+
+	// url that is already normalized has no original url
+	link = Link{URL: "https://example.com/a"}
+	if err := n.NormalizeLink(&link); err != nil {
+		t.Fatal(err)
+	}
+	if link.URL != "https://example.com/a" || link.OriginalURL != "" {
+		t.Fatalf("\ngot: %+v", link)
+	}
+}
@@ -0,0 +1,108 @@
+package linkgraph
+
+import "time"
+
+// TimeSource select where the timestamp of a write come from, backends take
+// it as option.
+type TimeSource int
+
+const (
+	// CallerTime persist the timestamp supplied by the caller as is.
+	CallerTime TimeSource = iota
+
+	// StoreTime ignore the timestamp supplied by the caller, the write is
+	// stamped with the store clock.
+	StoreTime
+)
+
+// RemoveOption configure RemoveStaleEdges.
+type RemoveOption func(*RemoveOptions)
+
+// RemoveOptions is the resolved set of RemoveOption, backends obtain it with
+// NewRemoveOptions.
+type RemoveOptions struct {
+	// report what would be removed without removing anything
+	DryRun bool
+}
+
+// NewRemoveOptions apply opts in order.
+func NewRemoveOptions(opts ...RemoveOption) RemoveOptions {
+	var o RemoveOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// DryRun make RemoveStaleEdges report the edges that would be removed while
+// leaving the graph untouched.
+func DryRun() RemoveOption {
+	return func(o *RemoveOptions) {
+		o.DryRun = true
+	}
+}
+
+// EdgeOption configure the Edges iterator.
+type EdgeOption func(*EdgeOptions)
+
+// EdgeOptions is the resolved set of EdgeOption, backends obtain it with
+// NewEdgeOptions.
+type EdgeOptions struct {
+	// skip edges with RelNofollow
+	SkipNofollow bool
+
+	// reconstruct the graph at that time, zero for the current graph
+	AsOf time.Time
+
+	// rewrite destination that redirect to the final target
+	ResolveRedirects bool
+}
+
+// NewEdgeOptions apply opts in order.
+func NewEdgeOptions(opts ...EdgeOption) EdgeOptions {
+	var o EdgeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// SkipNofollow make Edges skip edges with RelNofollow, e.g. for ranking that
+// must not follow them.
+func SkipNofollow() EdgeOption {
+	return func(o *EdgeOptions) {
+		o.SkipNofollow = true
+	}
+}
+
+// AsOf make Edges return the edges as they were at t instead of filtering
+// them by updateBefore: edges first seen at or before t that had not vanished
+// by t. vanished edges are only known to graph that keep the edge history.
+func AsOf(t time.Time) EdgeOption {
+	return func(o *EdgeOptions) {
+		o.AsOf = t
+	}
+}
+
+// ResolveRedirects make Edges return edges that end at an alias as ending at
+// the final target of its redirects, see Graph.ResolveRedirect. the alias is
+// kept in Edge.DstAlias and the ID of the edge is kept, so a source that link
+// to both the alias and the target has two edges to the target. edges keep
+// their position in the order of the alias. destination whose redirects loop
+// is left as is. the current redirects are used even with AsOf.
+func ResolveRedirects() EdgeOption {
+	return func(o *EdgeOptions) {
+		o.ResolveRedirects = true
+	}
+}
+
+// Match report whether edge pass the filters of o.
+func (o EdgeOptions) Match(edge *Edge, updateBefore time.Time) bool {
+	if o.SkipNofollow && edge.Rel.Has(RelNofollow) {
+		return false
+	}
+	if o.AsOf.IsZero() {
+		return edge.UpdateAt.Before(updateBefore)
+	}
+	return !edge.FirstSeen.After(o.AsOf) && (edge.VanishedAt.IsZero() || edge.VanishedAt.After(o.AsOf))
+}
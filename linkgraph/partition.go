@@ -0,0 +1,150 @@
+package linkgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxUUID is the largest ID of the uuid space.
+var MaxUUID = uuid.UUID{
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+}
+
+// Range is the [From, To) range of IDs given to Links and Edges. as there is
+// no ID after MaxUUID, range that end at MaxUUID include it.
+type Range struct {
+	From uuid.UUID
+	To   uuid.UUID
+}
+
+// Last return the largest ID inside the range, false if the range can't
+// contain any ID. backends use it to query the range with inclusive bound.
+func (r Range) Last() (uuid.UUID, bool) {
+	if r.To == MaxUUID {
+		return MaxUUID, true
+	}
+	if r.To == uuid.Nil {
+		return uuid.Nil, false
+	}
+
+	last := r.To
+	for i := len(last) - 1; i >= 0; i-- {
+		last[i]--
+		if last[i] != 0xff {
+			break
+		}
+	}
+	return last, true
+}
+
+// Partition split the whole uuid space into n contiguous ranges of (almost)
+// equal size, the result only depend on n so independent workers agree on
+// the ranges. the first range start at uuid.Nil and the last one end at
+// MaxUUID.
+func Partition(n int) ([]Range, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("partition: invalid number of partitions %d", n)
+	}
+
+	//calculate size of each partition as(2^128/n)
+	size := new(big.Int).Lsh(big.NewInt(1), 128)
+	size.Div(size, big.NewInt(int64(n)))
+
+	ranges := make([]Range, n)
+	for i := range ranges {
+		if i > 0 {
+			ranges[i].From = ranges[i-1].To
+		}
+
+		ranges[i].To = MaxUUID
+		if i < n-1 {
+			new(big.Int).Mul(size, big.NewInt(int64(i+1))).FillBytes(ranges[i].To[:])
+		}
+	}
+	return ranges, nil
+}
+
+// ScanLinks iterate links of every range retrieved before retrieveBefore and
+// call fn for each of them. at most concurrency ranges are scanned at the
+// same time, so fn must be safe for concurrent use.
+//
+// failure of a range (iterator error or error returned by fn) stop that range
+// only, the errors of all ranges are joined.
+func ScanLinks(ctx context.Context, g Graph, ranges []Range, concurrency int, retrieveBefore time.Time, fn func(r Range, link *Link) error) error {
+	return fanOut(ranges, concurrency, func(r Range) error {
+		it, err := g.Links(ctx, r.From, r.To, retrieveBefore)
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+
+		for it.Next() {
+			if err := fn(r, it.Link()); err != nil {
+				return err
+			}
+		}
+		if err := it.Error(); err != nil {
+			return err
+		}
+		return it.Close()
+	})
+}
+
+// ScanEdges is like ScanLinks for edges whose source is in the ranges, opts
+// are passed to Edges.
+func ScanEdges(ctx context.Context, g Graph, ranges []Range, concurrency int, updateBefore time.Time, fn func(r Range, edge *Edge) error, opts ...EdgeOption) error {
+	return fanOut(ranges, concurrency, func(r Range) error {
+		it, err := g.Edges(ctx, r.From, r.To, updateBefore, opts...)
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+
+		for it.Next() {
+			if err := fn(r, it.Edge()); err != nil {
+				return err
+			}
+		}
+		if err := it.Error(); err != nil {
+			return err
+		}
+		return it.Close()
+	})
+}
+
+// fanOut run scan for every range with at most concurrency goroutines.
+func fanOut(ranges []Range, concurrency int, scan func(r Range) error) error {
+	if concurrency < 1 || concurrency > len(ranges) {
+		concurrency = len(ranges)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(ranges))
+		sem  = make(chan struct{}, concurrency)
+	)
+	for i, r := range ranges {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, r Range) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := scan(r); err != nil {
+				errs[i] = fmt.Errorf("range [%s, %s): %w", r.From, r.To, err)
+			}
+		}(i, r)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
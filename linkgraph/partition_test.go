@@ -0,0 +1,74 @@
+package linkgraph
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_partition(t *testing.T) {
+	t.Run("ranges are contiguous", test_partition_contiguous)
+	t.Run("inclusive upper bound", test_range_last)
+	t.Run("invalid partition", test_partition_invalid)
+}
+
+func test_partition_contiguous(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 16, 1000} {
+		ranges, err := Partition(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ranges) != n {
+			t.Fatalf("\ngot: %d ranges\nexpect: %d", len(ranges), n)
+		}
+
+		if ranges[0].From != uuid.Nil || ranges[n-1].To != MaxUUID {
+			t.Fatalf("\ngot: [%v, %v]\nmessage: %v", ranges[0].From, ranges[n-1].To,
+				"partitions do not cover the whole uuid space")
+		}
+		for i := 1; i < n; i++ {
+			if ranges[i].From != ranges[i-1].To {
+				t.Fatalf("\ngot: %v\nexpect: %v\nmessage: gap between partition %d and %d",
+					ranges[i].From, ranges[i-1].To, i-1, i)
+			}
+			if ranges[i].From.String() <= ranges[i-1].From.String() {
+				t.Fatalf("partition %d is empty", i-1)
+			}
+		}
+	}
+
+	// same input, same ranges
+	a, _ := Partition(7)
+	b, _ := Partition(7)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("\ngot: %v\nexpect: %v", b[i], a[i])
+		}
+	}
+}
+
+func test_range_last(t *testing.T) {
+	tests := []struct {
+		to   string
+		last string
+		ok   bool
+	}{
+		{"ffffffff-ffff-ffff-ffff-ffffffffffff", "ffffffff-ffff-ffff-ffff-ffffffffffff", true},
+		{"80000000-0000-0000-0000-000000000000", "7fffffff-ffff-ffff-ffff-ffffffffffff", true},
+		{"00000000-0000-0000-0000-000000000010", "00000000-0000-0000-0000-00000000000f", true},
+		{"00000000-0000-0000-0000-000000000000", "00000000-0000-0000-0000-000000000000", false},
+	}
+
+	for _, tc := range tests {
+		last, ok := Range{To: uuid.MustParse(tc.to)}.Last()
+		if ok != tc.ok || last.String() != tc.last {
+			t.Fatalf("\ngot: %v %v\nexpect: %v %v", last, ok, tc.last, tc.ok)
+		}
+	}
+}
+
+func test_partition_invalid(t *testing.T) {
+	if _, err := Partition(0); err == nil {
+		t.Fatal("expected error for zero partition")
+	}
+}
@@ -0,0 +1,42 @@
+package linkgraph
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Redirect record that the alias link redirect to the target link, e.g. url
+// that respond with 301. a link is the alias of at most one redirect.
+type Redirect struct {
+	// link that redirect
+	Alias uuid.UUID
+
+	// link the alias redirect to
+	Target uuid.UUID
+
+	// HTTP status code of the redirect response
+	StatusCode int
+
+	// time the redirect was observed, the redirect of an alias is replaced
+	// only by upsert that is at least as recent.
+	ObservedAt time.Time
+}
+
+// FollowRedirects walk the chain of redirects that start at id using lookup,
+// it return the hops in order, or ErrRedirectLoop if the chain come back to
+// a link it already passed. link that does not redirect has empty chain.
+//
+// it is meant for graph that keep the redirects in memory.
+func FollowRedirects(id uuid.UUID, lookup func(alias uuid.UUID) *Redirect) ([]*Redirect, error) {
+	var chain []*Redirect
+	seen := map[uuid.UUID]bool{id: true}
+	for r := lookup(id); r != nil; r = lookup(r.Target) {
+		if seen[r.Target] {
+			return nil, ErrRedirectLoop
+		}
+		chain = append(chain, r)
+		seen[r.Target] = true
+	}
+	return chain, nil
+}
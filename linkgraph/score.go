@@ -0,0 +1,78 @@
+package linkgraph
+
+import "fmt"
+
+// ScoreKind identify a score computed over the graph and stored on links,
+// e.g. by the pagerank package.
+type ScoreKind uint8
+
+const (
+	// ScorePageRank is stored in Link.PageRank.
+	ScorePageRank ScoreKind = iota + 1
+
+	// ScoreTrustRank is stored in Link.TrustRank.
+	ScoreTrustRank
+
+	// ScoreHub is stored in Link.HubScore.
+	ScoreHub
+
+	// ScoreAuthority is stored in Link.AuthorityScore.
+	ScoreAuthority
+)
+
+func (k ScoreKind) String() string {
+	switch k {
+	case ScorePageRank:
+		return "pagerank"
+	case ScoreTrustRank:
+		return "trustrank"
+	case ScoreHub:
+		return "hub"
+	case ScoreAuthority:
+		return "authority"
+	}
+	return fmt.Sprintf("ScoreKind(%d)", uint8(k))
+}
+
+// Valid report whether k is a known score.
+func (k ScoreKind) Valid() bool {
+	return k >= ScorePageRank && k <= ScoreAuthority
+}
+
+// Score return the score of kind stored on link.
+func (l *Link) Score(kind ScoreKind) float64 {
+	switch kind {
+	case ScorePageRank:
+		return l.PageRank
+	case ScoreTrustRank:
+		return l.TrustRank
+	case ScoreHub:
+		return l.HubScore
+	case ScoreAuthority:
+		return l.AuthorityScore
+	}
+	return 0
+}
+
+// SetScore store the score of kind on link, unknown kind is ignored.
+func (l *Link) SetScore(kind ScoreKind, score float64) {
+	switch kind {
+	case ScorePageRank:
+		l.PageRank = score
+	case ScoreTrustRank:
+		l.TrustRank = score
+	case ScoreHub:
+		l.HubScore = score
+	case ScoreAuthority:
+		l.AuthorityScore = score
+	}
+}
+
+// CopyScores copy every score of src to l, graph use it to keep the scores
+// of a link across upserts.
+func (l *Link) CopyScores(src *Link) {
+	l.PageRank = src.PageRank
+	l.TrustRank = src.TrustRank
+	l.HubScore = src.HubScore
+	l.AuthorityScore = src.AuthorityScore
+}
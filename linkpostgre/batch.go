@@ -0,0 +1,273 @@
+package linkpostgre
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// UpsertLinks implements graph.Graph.
+//
+// the whole batch is sent as a single multi-row INSERT, links that share the
+// same url are merged beforehand keeping the latest retrieved_at. link whose
+// url can't be normalized is rejected with linkgraph.ErrInvalidURL.
+func (p *postgre) UpsertLinks(ctx context.Context, links []*linkgraph.Link) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	// duplicate url keep the latest retrieval
+	errs := make([]error, len(links))
+	urls := make([]string, len(links))
+	latest := make(map[string]linkgraph.Link, len(links))
+	for i, link := range links {
+		l := *link
+		if errs[i] = p.normalizer.NormalizeLink(&l); errs[i] != nil {
+			continue
+		}
+		if p.linkID != nil {
+			if l.ID, errs[i] = p.nameBasedID(&l); errs[i] != nil {
+				continue
+			}
+		}
+
+		urls[i] = l.URL
+		l.RetrievedAt = p.linkRetrievedAt(link.RetrievedAt)
+		if at, ok := latest[l.URL]; !ok || !l.RetrievedAt.Before(at.RetrievedAt) {
+			latest[l.URL] = l
+		}
+	}
+	if len(latest) == 0 {
+		return linkgraph.NewBatchError(errs)
+	}
+
+	var cols linkBatchColumns
+	for _, link := range latest {
+		cols.append(&link)
+	}
+
+	query, args := linkBatchUpsertQuery, []any{
+		cols.urls,
+		cols.retrievedAt,
+		cols.statusCode,
+		cols.contentType,
+		cols.contentLength,
+		cols.contentHash,
+		cols.title,
+		cols.lastModified,
+		cols.etag,
+		cols.originalURL,
+	}
+	if p.linkID != nil {
+		query, args = linkBatchUpsertByIDQuery, append(args, cols.ids)
+	}
+
+	rows, err := p.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("upsert links: %v", err)
+	}
+	defer rows.Close()
+
+	stored := make(map[string]*linkgraph.Link, len(latest))
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return fmt.Errorf("upsert links: %v", err)
+		}
+		stored[link.URL] = link
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("upsert links: %v", err)
+	}
+
+	for i, link := range links {
+		if errs[i] == nil {
+			*link = *stored[urls[i]]
+		}
+	}
+	return linkgraph.NewBatchError(errs)
+}
+
+// linkBatchColumns hold the links of a batch column by column, as expected by
+// unnest.
+type linkBatchColumns struct {
+	urls          []string
+	retrievedAt   []time.Time
+	statusCode    []int
+	contentType   []string
+	contentLength []int64
+	contentHash   [][]byte
+	title         []string
+	lastModified  []time.Time
+	etag          []string
+	originalURL   []string
+	ids           []uuid.UUID
+}
+
+func (c *linkBatchColumns) append(link *linkgraph.Link) {
+	c.urls = append(c.urls, link.URL)
+	c.retrievedAt = append(c.retrievedAt, link.RetrievedAt)
+	c.statusCode = append(c.statusCode, link.StatusCode)
+	c.contentType = append(c.contentType, link.ContentType)
+	c.contentLength = append(c.contentLength, link.ContentLength)
+	c.contentHash = append(c.contentHash, nilIfEmpty(link.ContentHash))
+	c.title = append(c.title, link.Title)
+	c.lastModified = append(c.lastModified, link.LastModified)
+	c.etag = append(c.etag, link.ETag)
+	c.originalURL = append(c.originalURL, link.OriginalURL)
+	c.ids = append(c.ids, link.ID)
+}
+
+// UpsertEdges implements graph.Graph.
+//
+// edges that reference unknown link are rejected with
+// linkgraph.ErrUnknownEdgeLinks, the rest is sent as a single multi-row INSERT
+// within the same transaction. with name-based IDs the unknown links are
+// created as placeholder instead.
+func (p *postgre) UpsertEdges(ctx context.Context, edges []*linkgraph.Edge) error {
+	if len(edges) == 0 {
+		return nil
+	}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("upsert edges: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if p.linkID != nil {
+		if err := createPlaceholders(ctx, tx, edges); err != nil {
+			return fmt.Errorf("upsert edges: %v", err)
+		}
+	}
+
+	known, err := existingLinks(ctx, tx, edges)
+	if err != nil {
+		return fmt.Errorf("upsert edges: %v", err)
+	}
+
+	type pair struct{ src, dst uuid.UUID }
+
+	// duplicate pair in the batch keep the latest edge
+	errs := make([]error, len(edges))
+	latest := make(map[pair]linkgraph.Edge, len(edges))
+	for i, edge := range edges {
+		if !known[edge.Src] || !known[edge.Dst] {
+			errs[i] = linkgraph.ErrUnknownEdgeLinks
+			continue
+		}
+
+		e := *edge
+		e.UpdateAt = p.edgeUpdateAt(edge.UpdateAt)
+		key := pair{edge.Src, edge.Dst}
+		if at, ok := latest[key]; !ok || !e.UpdateAt.Before(at.UpdateAt) {
+			latest[key] = e
+		}
+	}
+
+	var cols edgeBatchColumns
+	for _, edge := range latest {
+		cols.append(&edge)
+	}
+
+	stored := make(map[pair]*linkgraph.Edge, len(latest))
+	if len(latest) > 0 {
+		rows, err := tx.QueryxContext(ctx, edgeBatchUpsertQuery,
+			cols.srcs,
+			cols.dsts,
+			cols.updateAt,
+			cols.anchorText,
+			cols.rel,
+			cols.boilerplate,
+			cols.occurrences,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert edges: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			edge, err := scanEdge(rows)
+			if err != nil {
+				return fmt.Errorf("upsert edges: %v", err)
+			}
+			stored[pair{edge.Src, edge.Dst}] = edge
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("upsert edges: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("upsert edges: %v", err)
+	}
+
+	for i, edge := range edges {
+		if errs[i] == nil {
+			*edge = *stored[pair{edge.Src, edge.Dst}]
+		}
+	}
+	return linkgraph.NewBatchError(errs)
+}
+
+// createPlaceholders create placeholder link for the edges endpoint that are
+// not in links table yet, it is used with name-based IDs.
+func createPlaceholders(ctx context.Context, tx *sqlx.Tx, edges []*linkgraph.Edge) error {
+	ids := make([]uuid.UUID, 0, 2*len(edges))
+	for _, edge := range edges {
+		ids = append(ids, edge.Src, edge.Dst)
+	}
+
+	_, err := tx.ExecContext(ctx, linkPlaceholderQuery, ids)
+	return err
+}
+
+// existingLinks return the set of edges endpoint that exist in links table.
+func existingLinks(ctx context.Context, tx *sqlx.Tx, edges []*linkgraph.Edge) (map[uuid.UUID]bool, error) {
+	ids := make([]uuid.UUID, 0, 2*len(edges))
+	for _, edge := range edges {
+		ids = append(ids, edge.Src, edge.Dst)
+	}
+
+	rows, err := tx.QueryxContext(ctx, linkExistQuery, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	known := make(map[uuid.UUID]bool, len(ids))
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		known[id] = true
+	}
+	return known, rows.Err()
+}
+
+// edgeBatchColumns hold the edges of a batch column by column, as expected by
+// unnest.
+type edgeBatchColumns struct {
+	srcs        []uuid.UUID
+	dsts        []uuid.UUID
+	updateAt    []time.Time
+	anchorText  []string
+	rel         []int16
+	boilerplate []bool
+	occurrences []int
+}
+
+func (c *edgeBatchColumns) append(edge *linkgraph.Edge) {
+	c.srcs = append(c.srcs, edge.Src)
+	c.dsts = append(c.dsts, edge.Dst)
+	c.updateAt = append(c.updateAt, edge.UpdateAt)
+	c.anchorText = append(c.anchorText, edge.AnchorText)
+	c.rel = append(c.rel, int16(edge.Rel))
+	c.boilerplate = append(c.boilerplate, edge.Boilerplate)
+	c.occurrences = append(c.occurrences, edge.Occurrences)
+}
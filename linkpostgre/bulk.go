@@ -0,0 +1,273 @@
+package linkpostgre
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// LinkSource feed links into BulkImport, linkgraph.LinkIterator satisfy it.
+type LinkSource interface {
+	Next() bool
+	Link() *linkgraph.Link
+	Error() error
+}
+
+// BulkEdge is an edge of a bulk import, unlike linkgraph.Edge its endpoints
+// are referenced by url so edges can be seeded before link IDs are known.
+type BulkEdge struct {
+	SrcURL string
+	DstURL string
+}
+
+// EdgeSource feed edges into BulkImport.
+type EdgeSource interface {
+	Next() bool
+	Edge() *BulkEdge
+	Error() error
+}
+
+// BulkResult report the outcome of BulkImport.
+type BulkResult struct {
+	LinksInserted int64
+	LinksUpdated  int64
+
+	// LinksSkipped count links whose url can't be normalized.
+	LinksSkipped int64
+
+	EdgesInserted int64
+	EdgesUpdated  int64
+
+	// EdgesSkipped count edge rows that duplicate another row of the import,
+	// reference url that is not part of the graph or url that can't be
+	// normalized.
+	EdgesSkipped int64
+}
+
+// BulkImport load links and then edges into the graph using COPY FROM STDIN.
+// rows are first streamed into temporary staging tables and then merged into
+// links and edges with the same semantics as UpsertLink and UpsertEdge.
+// the whole import is applied in a single transaction. both source may be nil.
+func (p *postgre) BulkImport(ctx context.Context, links LinkSource, edges EdgeSource) (*BulkResult, error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bulk import: %v", err)
+	}
+	defer conn.Close()
+
+	var res BulkResult
+	err = conn.Raw(func(driverConn any) error {
+		pgConn, err := pgxConn(driverConn)
+		if err != nil {
+			return err
+		}
+
+		return pgx.BeginFunc(ctx, pgConn, func(tx pgx.Tx) error {
+			if links != nil {
+				if err := p.bulkLinks(ctx, tx, links, &res); err != nil {
+					return fmt.Errorf("links: %v", err)
+				}
+			}
+			if edges != nil {
+				if err := p.bulkEdges(ctx, tx, edges, &res); err != nil {
+					return fmt.Errorf("edges: %v", err)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bulk import: %v", err)
+	}
+
+	return &res, nil
+}
+
+func (p *postgre) bulkLinks(ctx context.Context, tx pgx.Tx, links LinkSource, res *BulkResult) error {
+	if _, err := tx.Exec(ctx, createLinkStagingQuery); err != nil {
+		return err
+	}
+
+	copySrc := linkCopySource{src: links, retrievedAt: p.linkRetrievedAt, normalizer: p.normalizer, linkID: p.linkID}
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{"links_staging"}, []string{"url", "retrieved_at", "status_code", "content_type", "content_length", "content_hash", "title", "last_modified", "etag", "original_url", "id"}, &copySrc)
+	if err != nil {
+		return err
+	}
+	res.LinksSkipped = copySrc.skipped
+
+	query := mergeLinkStagingQuery
+	if p.linkID != nil {
+		query = mergeLinkStagingByIDQuery
+	}
+	return tx.QueryRow(ctx, query).Scan(&res.LinksInserted, &res.LinksUpdated)
+}
+
+func (p *postgre) bulkEdges(ctx context.Context, tx pgx.Tx, edges EdgeSource, res *BulkResult) error {
+	if _, err := tx.Exec(ctx, createEdgeStagingQuery); err != nil {
+		return err
+	}
+
+	copySrc := edgeCopySource{src: edges, normalizer: p.normalizer, linkID: p.linkID}
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{"edges_staging"}, []string{"src_url", "dst_url", "src_id", "dst_id"}, &copySrc)
+	if err != nil {
+		return err
+	}
+
+	// with name-based IDs edges don't need their links to be imported
+	query := mergeEdgeStagingQuery
+	if p.linkID != nil {
+		if _, err := tx.Exec(ctx, edgeStagingPlaceholderQuery); err != nil {
+			return err
+		}
+		query = mergeEdgeStagingByIDQuery
+	}
+	if err := tx.QueryRow(ctx, query, p.now()).Scan(&res.EdgesInserted, &res.EdgesUpdated); err != nil {
+		return err
+	}
+
+	res.EdgesSkipped = copySrc.skipped + copied - res.EdgesInserted - res.EdgesUpdated
+	return nil
+}
+
+// pgxConn unwrap the pgx connection of database/sql driver connection. the
+// driver may be wrapped by instrumentation (e.g. otelsql), the wrapper must
+// keep the original connection in an exported field, embedded or not.
+func pgxConn(driverConn any) (*pgx.Conn, error) {
+	for {
+		if c, ok := driverConn.(*stdlib.Conn); ok {
+			return c.Conn(), nil
+		}
+
+		inner, ok := wrappedConn(driverConn)
+		if !ok {
+			return nil, fmt.Errorf("driver connection %T is not pgx", driverConn)
+		}
+		driverConn = inner
+	}
+}
+
+// wrappedConn return the driver connection held by the first exported field
+// of wrapper that hold one, whatever its name.
+func wrappedConn(wrapper any) (driver.Conn, bool) {
+	v := reflect.Indirect(reflect.ValueOf(wrapper))
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanInterface() {
+			continue
+		}
+		if inner, ok := field.Interface().(driver.Conn); ok {
+			return inner, true
+		}
+	}
+	return nil, false
+}
+
+var _ pgx.CopyFromSource = (*linkCopySource)(nil)
+
+type linkCopySource struct {
+	src LinkSource
+
+	// apply the store time source
+	retrievedAt func(time.Time) time.Time
+
+	// link whose url can't be normalized is skipped
+	normalizer *linkgraph.Normalizer
+	skipped    int64
+	link       linkgraph.Link
+
+	// name-based ID of the link, nil for random ID
+	linkID func(url string) uuid.UUID
+}
+
+func (s *linkCopySource) Next() bool {
+	for s.src.Next() {
+		s.link = *s.src.Link()
+		if err := s.normalizer.NormalizeLink(&s.link); err != nil {
+			s.skipped++
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (s *linkCopySource) Values() ([]any, error) {
+	link := &s.link
+	return []any{
+		link.URL,
+		s.retrievedAt(link.RetrievedAt),
+		link.StatusCode,
+		link.ContentType,
+		link.ContentLength,
+		nilIfEmpty(link.ContentHash),
+		link.Title,
+		link.LastModified,
+		link.ETag,
+		link.OriginalURL,
+		s.id(link.URL),
+	}, nil
+}
+
+// id return nil so the staged link get random ID.
+func (s *linkCopySource) id(url string) any {
+	if s.linkID == nil {
+		return nil
+	}
+	return s.linkID(url)
+}
+
+func (s *linkCopySource) Err() error {
+	return s.src.Error()
+}
+
+var _ pgx.CopyFromSource = (*edgeCopySource)(nil)
+
+type edgeCopySource struct {
+	src EdgeSource
+
+	// edge with endpoint url that can't be normalized is skipped, with
+	// name-based IDs it would otherwise get a placeholder link
+	normalizer *linkgraph.Normalizer
+	skipped    int64
+	srcURL     string
+	dstURL     string
+
+	// name-based ID of the endpoints, nil for random ID
+	linkID func(url string) uuid.UUID
+}
+
+func (s *edgeCopySource) Next() bool {
+	for s.src.Next() {
+		edge := s.src.Edge()
+		src, srcErr := s.normalizer.Normalize(edge.SrcURL)
+		dst, dstErr := s.normalizer.Normalize(edge.DstURL)
+		if srcErr != nil || dstErr != nil {
+			s.skipped++
+			continue
+		}
+		s.srcURL, s.dstURL = src, dst
+		return true
+	}
+	return false
+}
+
+func (s *edgeCopySource) Values() ([]any, error) {
+	if s.linkID == nil {
+		return []any{s.srcURL, s.dstURL, nil, nil}, nil
+	}
+	return []any{s.srcURL, s.dstURL, s.linkID(s.srcURL), s.linkID(s.dstURL)}, nil
+}
+
+func (s *edgeCopySource) Err() error {
+	return s.src.Error()
+}
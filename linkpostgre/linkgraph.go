@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,104 +14,239 @@ import (
 
 var _ linkgraph.Graph = (*postgre)(nil)
 
+// all timestamps are stored as TIMESTAMPTZ and returned in UTC.
 type postgre struct {
 	db *sqlx.DB
+
+	clock    func() time.Time
+	linkTime TimeSource
+	edgeTime TimeSource
+
+	// number of rows fetched per page by iterators
+	pageSize int
+
+	// removed edges are kept in edge_history
+	edgeHistory bool
+
+	// url of links are normalized before they reach the database
+	normalizer *linkgraph.Normalizer
+
+	// linkID derive the ID of link from its url, nil for random ID
+	linkID func(url string) uuid.UUID
 }
 
-func New(db *sqlx.DB) *postgre {
+// New return graph backed by db, the schema is migrated to the latest
+// version before returning.
+func New(db *sqlx.DB, opts ...Option) (*postgre, error) {
 	p := postgre{
-		db: db,
+		db:       db,
+		clock:    time.Now,
+		linkTime: CallerTime,
+		edgeTime: StoreTime,
+		pageSize: defaultPageSize,
 	}
-	if err := p.Migrate(); err != nil {
-		log.Fatal(err)
+	for _, opt := range opts {
+		opt(&p)
 	}
-	return &p
-}
 
-// ==============
+	if err := p.Migrate(context.Background()); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
 
-// const dropLinksTable = `
-// DROP TABLE IF EXISTS links;
-// `
+// LookupLink implements graph.Graph.
+func (p *postgre) LookupLink(ctx context.Context, id uuid.UUID) (*linkgraph.Link, error) {
+	link, err := scanLink(p.db.QueryRowxContext(ctx, lookupLinkQuery, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, linkgraph.ErrNotFound
+		}
+		return nil, fmt.Errorf("lookup link: %v", err)
+	}
 
-// const dropEdgeTable = `
-// DROP TABLE IF EXISTS edges;
-// `
+	return link, nil
+}
 
-func (p *postgre) Migrate() error {
-	//link table
-	_, err := p.db.ExecContext(context.TODO(), createLinkTableQuery)
+// LookupLinkByURL implements graph.Graph.
+func (p *postgre) LookupLinkByURL(ctx context.Context, url string) (*linkgraph.Link, error) {
+	url, err := p.normalizer.Normalize(url)
 	if err != nil {
-		return fmt.Errorf("create table: %v", err)
+		// there is no link with url that can't be normalized
+		return nil, linkgraph.ErrNotFound
 	}
 
-	//edge table
-	_, err = p.db.ExecContext(context.TODO(), createEdgeTableQuery)
+	link, err := scanLink(p.db.QueryRowxContext(ctx, lookupLinkByURLQuery, url))
 	if err != nil {
-		return fmt.Errorf("create table: %v", err)
+		if err == sql.ErrNoRows {
+			return nil, linkgraph.ErrNotFound
+		}
+		return nil, fmt.Errorf("lookup link by url: %v", err)
 	}
 
-	return nil
+	return link, nil
 }
 
-// LookupLink implements graph.Graph.
-func (p *postgre) LookupLink(id uuid.UUID) (*linkgraph.Link, error) {
-	// queryCtx, cancel := context.WithCancel(p.ctx)
-	// defer cancel()
+// ResolveURLs implements graph.Graph.
+func (p *postgre) ResolveURLs(ctx context.Context, urls []string) (map[string]uuid.UUID, error) {
+	resolved := make(map[string]uuid.UUID, len(urls))
+	if len(urls) == 0 {
+		return resolved, nil
+	}
 
-	var link linkgraph.Link
+	// the result is keyed by the requested urls
+	requested := p.normalizer.NormalizeAll(urls)
+	normalized := make([]string, 0, len(requested))
+	for url := range requested {
+		normalized = append(normalized, url)
+	}
+
+	rows, err := p.db.QueryxContext(ctx, resolveURLsQuery, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("resolve urls: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id  uuid.UUID
+			url string
+		)
+		if err := rows.Scan(&id, &url); err != nil {
+			return nil, fmt.Errorf("resolve urls: %v", err)
+		}
+		for _, rawURL := range requested[url] {
+			resolved[rawURL] = id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("resolve urls: %v", err)
+	}
+
+	return resolved, nil
+}
 
-	err := p.db.QueryRowx(lookupLinkQuery, id).Scan(&link.ID, &link.URL, &link.RetrievedAt)
+// LookupEdge implements graph.Graph.
+func (p *postgre) LookupEdge(ctx context.Context, id uuid.UUID) (*linkgraph.Edge, error) {
+	edge, err := scanEdge(p.db.QueryRowxContext(ctx, lookupEdgeQuery, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, linkgraph.ErrNotFound
 		}
-		return nil, fmt.Errorf("lookup link: %v", err)
+		return nil, fmt.Errorf("lookup edge: %v", err)
 	}
 
-	return &link, nil
+	return edge, nil
 }
 
 // RemoveStaleEdges implements graph.Graph.
-func (p *postgre) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error {
-	// queryCtx, cancel := context.WithCancel(p.ctx)
-	// defer cancel()
+func (p *postgre) RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time, opts ...linkgraph.RemoveOption) (*linkgraph.RemovedEdges, error) {
+	query := edgeRemoveStaleQuery
+	if p.edgeHistory {
+		query = edgeRemoveStaleHistoryQuery
+	}
+	if linkgraph.NewRemoveOptions(opts...).DryRun {
+		query = edgeSelectStaleQuery
+	}
 
-	_, err := p.db.Exec(edgeRemoveStaleQuery, fromID, updatedBefore.UTC())
-	if err != nil {
-		return fmt.Errorf("remove stale edge: %v", err)
+	var removed linkgraph.RemovedEdges
+	if err := p.db.SelectContext(ctx, &removed.DstIDs, query, fromID, updatedBefore); err != nil {
+		return nil, fmt.Errorf("remove stale edge: %v", err)
 	}
 
-	return nil
+	removed.Count = int64(len(removed.DstIDs))
+	return &removed, nil
 }
 
 // UpsertLink implements graph.Graph.
-// TODO: make fix time standar so no need to call UTC() every time
-func (p *postgre) UpsertLink(link *linkgraph.Link) error {
-	// queryCtx, cancel := context.WithCancel(p.ctx)
-	// defer cancel()
+func (p *postgre) UpsertLink(ctx context.Context, link *linkgraph.Link) error {
+	// caller link is left untouched on error
+	l := *link
+	if err := p.normalizer.NormalizeLink(&l); err != nil {
+		return fmt.Errorf("upsert link: %w", err)
+	}
 
-	link.RetrievedAt = link.RetrievedAt.UTC()
-	err := p.db.QueryRowx(linkUpsertQuery, link.URL, link.RetrievedAt).Scan(
-		&link.ID,
-		&link.RetrievedAt,
-	)
+	args := []any{
+		l.URL,
+		p.linkRetrievedAt(l.RetrievedAt),
+		l.StatusCode,
+		l.ContentType,
+		l.ContentLength,
+		nilIfEmpty(l.ContentHash),
+		l.Title,
+		l.LastModified,
+		l.ETag,
+		l.OriginalURL,
+	}
+	query := linkUpsertQuery
+	if p.linkID != nil {
+		id, err := p.nameBasedID(&l)
+		if err != nil {
+			return fmt.Errorf("upsert link: %w", err)
+		}
+		query, args = linkUpsertByIDQuery, append(args, id)
+	}
+
+	stored, err := scanLink(p.db.QueryRowxContext(ctx, query, args...))
 	if err != nil {
 		return fmt.Errorf("upsert link: %v ", err)
 	}
 
+	*link = *stored
 	return nil
 }
 
+// nameBasedID return the ID of link, the ID supplied by caller must match.
+func (p *postgre) nameBasedID(link *linkgraph.Link) (uuid.UUID, error) {
+	id := p.linkID(link.URL)
+	if link.ID != uuid.Nil && link.ID != id {
+		return uuid.Nil, linkgraph.ErrLinkIDMismatch
+	}
+	return id, nil
+}
+
 // UpsertEdge implements graph.Graph.
-// TODO: make fix time standar so no need to call UTC() every time
-func (p *postgre) UpsertEdge(edge *linkgraph.Edge) error {
-	// queryCtx, cancel := context.WithCancel(p.ctx)
-	// defer cancel()
+func (p *postgre) UpsertEdge(ctx context.Context, edge *linkgraph.Edge) error {
+	if p.linkID != nil {
+		return p.upsertEdgeWithPlaceholders(ctx, edge)
+	}
 
-	edge.UpdateAt = edge.UpdateAt.UTC()
+	return p.upsertEdge(ctx, p.db, edge)
+}
 
-	err := p.db.QueryRowx(edgeUpsertQuery, edge.Src, edge.Dst).Scan(&edge.ID, &edge.UpdateAt)
+// upsertEdgeWithPlaceholders create the unknown links of edge as placeholder
+// before upserting it.
+func (p *postgre) upsertEdgeWithPlaceholders(ctx context.Context, edge *linkgraph.Edge) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("edge upsert: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, linkPlaceholderQuery, []uuid.UUID{edge.Src, edge.Dst}); err != nil {
+		return fmt.Errorf("edge upsert: %v", err)
+	}
+	if err := p.upsertEdge(ctx, tx, edge); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("edge upsert: %v", err)
+	}
+	return nil
+}
+
+func (p *postgre) upsertEdge(ctx context.Context, q sqlx.QueryerContext, edge *linkgraph.Edge) error {
+	row := q.QueryRowxContext(ctx, edgeUpsertQuery,
+		edge.Src,
+		edge.Dst,
+		p.edgeUpdateAt(edge.UpdateAt),
+		edge.AnchorText,
+		int16(edge.Rel),
+		edge.Boilerplate,
+		edge.Occurrences,
+	)
+	stored, err := scanEdge(row)
 	if err != nil {
 		pgErr, ok := err.(*pgconn.PgError)
 		if ok {
@@ -125,63 +259,202 @@ func (p *postgre) UpsertEdge(edge *linkgraph.Edge) error {
 		return fmt.Errorf("edge upsert: %v", err)
 
 	}
+
+	*edge = *stored
+	return nil
+}
+
+// RemoveLink implements graph.Graph.
+func (p *postgre) RemoveLink(ctx context.Context, id uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, linkRemoveQuery, id)
+	if err != nil {
+		return fmt.Errorf("remove link: %v", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("remove link: %v", err)
+	}
+	if n == 0 {
+		return linkgraph.ErrNotFound
+	}
 	return nil
 }
 
-// Links implements graph.Graph.
-func (p *postgre) Links(fromID uuid.UUID, toID uuid.UUID, accessBefore time.Time) (linkgraph.LinkIterator, error) {
-	// queryCtx, cancel := context.WithCancel(p.ctx)
+// RemoveStaleLinks implements graph.Graph.
+func (p *postgre) RemoveStaleLinks(ctx context.Context, retrievedBefore time.Time) (int64, error) {
+	n, err := p.removeLinks(ctx, linkRemoveStaleQuery, retrievedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("remove stale links: %v", err)
+	}
+	return n, nil
+}
 
-	// rows := p.db.QueryRowxContext(context.Background(), linksQuery, fromID, toID, retrieveBefore.UTC())
-	rows, err := p.db.Queryx(linksIterationQuery, fromID, toID, accessBefore.UTC())
+// RemoveOrphanLinks implements graph.Graph.
+func (p *postgre) RemoveOrphanLinks(ctx context.Context, retrievedBefore time.Time) (int64, error) {
+	n, err := p.removeLinks(ctx, linkRemoveOrphanQuery, retrievedBefore)
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("remove orphan links: %v", err)
+	}
+	return n, nil
+}
+
+func (p *postgre) removeLinks(ctx context.Context, query string, retrievedBefore time.Time) (int64, error) {
+	res, err := p.db.ExecContext(ctx, query, retrievedBefore, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Links implements graph.Graph.
+//
+// links are fetched page by page using keyset pagination ordered by id, the
+// connection is returned to the pool between pages so a slow consumer never
+// hold it for the whole iteration.
+func (p *postgre) Links(ctx context.Context, fromID uuid.UUID, toID uuid.UUID, accessBefore time.Time) (linkgraph.LinkIterator, error) {
+	lastID, ok := linkgraph.Range{From: fromID, To: toID}.Last()
+	it := linkIterator{
+		p:            p,
+		ctx:          ctx,
+		lastID:       lastID,
+		accessBefore: accessBefore,
+		done:         !ok,
+	}
+	if it.done {
+		return &it, nil
 	}
 
-	linkIterator := linkIterator{
-		rows:    rows,
-		lastErr: nil,
+	// first page is fetched eagerly so invalid query fail here
+	if err := it.fetch(linksFirstPageQuery, fromID); err != nil {
+		return nil, fmt.Errorf("link iterator: %v", err)
 	}
 
-	return &linkIterator, nil
+	return &it, nil
 }
 
 //==========
 
 // Edges implements graph.Graph.
-func (p *postgre) Edges(fromID uuid.UUID, toID uuid.UUID, updateBefore time.Time) (linkgraph.EdgeIterator, error) {
-	// queryCtx, cancel := context.WithCancel(p.ctx)
+//
+// edges are fetched page by page using keyset pagination ordered by
+// (src, dst), see Links.
+func (p *postgre) Edges(ctx context.Context, fromID uuid.UUID, toID uuid.UUID, updateBefore time.Time, opts ...linkgraph.EdgeOption) (linkgraph.EdgeIterator, error) {
+	o := linkgraph.NewEdgeOptions(opts...)
+
+	firstQuery, nextQuery := edgesFirstPageQuery, edgesNextPageQuery
+	if !o.AsOf.IsZero() {
+		firstQuery, nextQuery = edgesAsOfFirstPageQuery, edgesAsOfNextPageQuery
+		updateBefore = o.AsOf
+	}
 
-	//find edges row
-	rows, err := p.db.Queryx(edgesIterationQuery, fromID, toID, updateBefore.UTC())
-	if err != nil {
+	lastID, ok := linkgraph.Range{From: fromID, To: toID}.Last()
+	it := edgeIterator{
+		p:         p,
+		ctx:       ctx,
+		args:      []any{lastID, updateBefore, o.SkipNofollow},
+		nextQuery: nextQuery,
+		nextKey:   func(last *linkgraph.Edge) []any { return []any{last.Src, last.Dst} },
+		done:      !ok,
+
+		resolveRedirects: o.ResolveRedirects,
+	}
+	if it.done {
+		return &it, nil
+	}
+
+	if err := it.fetch(firstQuery, fromID); err != nil {
 		return nil, fmt.Errorf("edge iterator: %v", err)
 	}
 
-	edgeIterator := edgeIterator{
-		rows:    rows,
-		lastErr: err,
+	return &it, nil
+}
+
+// OutEdges implements graph.Graph.
+func (p *postgre) OutEdges(ctx context.Context, linkID uuid.UUID) (linkgraph.EdgeIterator, error) {
+	it := edgeIterator{
+		p:         p,
+		ctx:       ctx,
+		args:      []any{linkID},
+		nextQuery: outEdgesNextPageQuery,
+		nextKey:   func(last *linkgraph.Edge) []any { return []any{last.Dst} },
+	}
+
+	if err := it.fetch(outEdgesFirstPageQuery); err != nil {
+		return nil, fmt.Errorf("out edges: %v", err)
+	}
+
+	return &it, nil
+}
+
+// InEdges implements graph.Graph.
+func (p *postgre) InEdges(ctx context.Context, linkID uuid.UUID) (linkgraph.EdgeIterator, error) {
+	it := edgeIterator{
+		p:         p,
+		ctx:       ctx,
+		args:      []any{linkID},
+		nextQuery: inEdgesNextPageQuery,
+		nextKey:   func(last *linkgraph.Edge) []any { return []any{last.Src} },
+	}
+
+	if err := it.fetch(inEdgesFirstPageQuery); err != nil {
+		return nil, fmt.Errorf("in edges: %v", err)
 	}
 
-	return &edgeIterator, nil
+	return &it, nil
 }
 
 //==========
 
 var _ linkgraph.LinkIterator = (*linkIterator)(nil)
 
-// linkedge iterator
+// linkIterator hold at most one page of links in memory.
 type linkIterator struct {
-	rows *sqlx.Rows
+	p   *postgre
+	ctx context.Context
+
+	// inclusive upper bound of the range
+	lastID       uuid.UUID
+	accessBefore time.Time
+
+	page []*linkgraph.Link
+	curr int
+	// last page is shorter than page size
+	done bool
 
 	link *linkgraph.Link
 
 	lastErr error
 }
 
+// fetch replace the current page with the next one.
+func (it *linkIterator) fetch(query string, args ...any) error {
+	rows, err := it.p.db.QueryxContext(it.ctx, query, append(args, it.lastID, it.accessBefore, it.p.pageSize)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	it.page, it.curr = it.page[:0], 0
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return err
+		}
+		it.page = append(it.page, link)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	it.done = len(it.page) < it.p.pageSize
+	return nil
+}
+
 // Close implements graph.LinkIterator.
 func (it *linkIterator) Close() error {
-	return it.rows.Close()
+	it.page, it.done = nil, true
+	return nil
 }
 
 // Error implements graph.LinkIterator.
@@ -196,37 +469,110 @@ func (it *linkIterator) Link() *linkgraph.Link {
 
 // Next implements graph.LinkIterator.
 func (it *linkIterator) Next() bool {
-
-	ok := it.rows.Next()
-	if !ok {
+	if it.lastErr = it.ctx.Err(); it.lastErr != nil {
 		return false
 	}
 
-	var link linkgraph.Link
-	it.lastErr = it.rows.Scan(&link.ID, &link.URL, &link.RetrievedAt) //Scan(&link)
-	if it.lastErr != nil {
-		return false
+	if it.curr >= len(it.page) {
+		if it.done {
+			return false
+		}
+		if it.lastErr = it.fetch(linksNextPageQuery, it.link.ID); it.lastErr != nil {
+			return false
+		}
+		if len(it.page) == 0 {
+			return false
+		}
 	}
 
-	it.link = &link
+	it.link = it.page[it.curr]
+	it.curr++
 	return true
-
 }
 
 var _ linkgraph.EdgeIterator = (*edgeIterator)(nil)
 
+// edgeIterator hold at most one page of edges in memory.
 type edgeIterator struct {
-	rows *sqlx.Rows
+	p   *postgre
+	ctx context.Context
+
+	// parameters of every page query, they follow the keyset parameters
+	args []any
+	// nextQuery fetch the page after the key returned by nextKey
+	nextQuery string
+	nextKey   func(last *linkgraph.Edge) []any
+	// key of the last edge of the page
+	next []any
+
+	// rewrite destination that redirect to the final target
+	resolveRedirects bool
+
+	page []*linkgraph.Edge
+	curr int
+	// last page is shorter than page size
+	done bool
 
 	edge *linkgraph.Edge
 
 	lastErr error
-	// cancelFn context.CancelFunc
+}
+
+// fetch replace the current page with the next one.
+func (it *edgeIterator) fetch(query string, key ...any) error {
+	args := append(append(key, it.args...), it.p.pageSize)
+	rows, err := it.p.db.QueryxContext(it.ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	it.page, it.curr = it.page[:0], 0
+	for rows.Next() {
+		edge, err := scanEdge(rows)
+		if err != nil {
+			return err
+		}
+		it.page = append(it.page, edge)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	it.done = len(it.page) < it.p.pageSize
+	if len(it.page) > 0 {
+		it.next = it.nextKey(it.page[len(it.page)-1])
+	}
+	if it.resolveRedirects {
+		return it.rewriteAliases()
+	}
+	return nil
+}
+
+// rewriteAliases replace destination of the page that redirect with its final
+// target.
+func (it *edgeIterator) rewriteAliases() error {
+	dsts := make([]uuid.UUID, len(it.page))
+	for i, edge := range it.page {
+		dsts[i] = edge.Dst
+	}
+
+	chains, err := it.p.redirectChains(it.ctx, dsts)
+	if err != nil {
+		return err
+	}
+	for _, edge := range it.page {
+		if target, ok := chains[edge.Dst].target(); ok {
+			edge.DstAlias, edge.Dst = edge.Dst, target
+		}
+	}
+	return nil
 }
 
 // Close implements linkgraph.EdgeIterator.
 func (it *edgeIterator) Close() error {
-	return it.rows.Close()
+	it.page, it.done = nil, true
+	return nil
 }
 
 // Edge implements linkgraph.EdgeIterator.
@@ -241,17 +587,98 @@ func (it *edgeIterator) Error() error {
 
 // Next implements linkgraph.EdgeIterator.
 func (it *edgeIterator) Next() bool {
-	ok := it.rows.Next()
-	if !ok {
+	if it.lastErr = it.ctx.Err(); it.lastErr != nil {
 		return false
 	}
 
-	var edge linkgraph.Edge
-	it.lastErr = it.rows.Scan(&edge.ID, &edge.Src, &edge.Dst, &edge.UpdateAt)
-	if it.lastErr != nil {
-		return false
+	if it.curr >= len(it.page) {
+		if it.done {
+			return false
+		}
+		if it.lastErr = it.fetch(it.nextQuery, it.next...); it.lastErr != nil {
+			return false
+		}
+		if len(it.page) == 0 {
+			return false
+		}
 	}
 
-	it.edge = &edge
+	it.edge = it.page[it.curr]
+	it.curr++
 	return true
 }
+
+//==========
+
+// scanner is implemented by *sqlx.Row and *sqlx.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanLink read link row selected as linkColumns.
+func scanLink(row scanner) (*linkgraph.Link, error) {
+	var link linkgraph.Link
+	err := row.Scan(
+		&link.ID,
+		&link.URL,
+		&link.OriginalURL,
+		&link.RetrievedAt,
+		&link.StatusCode,
+		&link.ContentType,
+		&link.ContentLength,
+		&link.ContentHash,
+		&link.Title,
+		&link.LastModified,
+		&link.ETag,
+		&link.PageRank,
+		&link.TrustRank,
+		&link.HubScore,
+		&link.AuthorityScore,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	link.RetrievedAt = link.RetrievedAt.UTC()
+	link.LastModified = link.LastModified.UTC()
+	link.ContentHash = nilIfEmpty(link.ContentHash)
+	return &link, nil
+}
+
+// nilIfEmpty store empty content hash as NULL so it read back as nil.
+func nilIfEmpty(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
+
+// scanEdge read edge row selected as edgeColumns.
+func scanEdge(row scanner) (*linkgraph.Edge, error) {
+	var (
+		edge                  linkgraph.Edge
+		rel                   int16
+		firstSeen, vanishedAt sql.NullTime
+	)
+	err := row.Scan(
+		&edge.ID,
+		&edge.Src,
+		&edge.Dst,
+		&edge.UpdateAt,
+		&edge.AnchorText,
+		&rel,
+		&edge.Boilerplate,
+		&edge.Occurrences,
+		&firstSeen,
+		&vanishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	edge.UpdateAt = edge.UpdateAt.UTC()
+	edge.FirstSeen = firstSeen.Time.UTC()
+	edge.VanishedAt = vanishedAt.Time.UTC()
+	edge.Rel = linkgraph.EdgeRel(rel)
+	return &edge, nil
+}
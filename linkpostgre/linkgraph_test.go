@@ -2,9 +2,9 @@ package linkpostgre
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"math/big"
 	"sort"
 	"sync"
 	"testing"
@@ -13,43 +13,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/odit-bit/linkstore/linkgraph"
+	"github.com/odit-bit/linkstore/linkgraph/graphtest"
+	"github.com/uptrace/opentelemetry-go-extra/otelsqlx"
 )
 
-type Migrate struct {
-	Create string
-	Drop   string
-}
-
-var linkTable = Migrate{
-	Create: `
-		CREATE TABLE IF NOT EXISTS links(
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			url text UNIQUE,
-			retrieved_at TIMESTAMP
-		);
-	`,
-	Drop: `
-		DROP TABLE IF EXISTS links;
-	`,
-}
-
-var edgeTable = Migrate{
-	Create: `
-		CREATE TABLE IF NOT EXISTS edges(
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			src UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
-			dst UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
-			update_at TIMESTAMP,
-			CONSTRAINT edge_links UNIQUE(src,dst)
-		);
-	`,
-	Drop: `
-		DROP TABLE IF EXISTS edges;
-	`,
-}
+const testDSN = "host=localhost user=development password=credential dbname=development sslmode=disable"
 
 var pg = func() *postgre {
-	conn, err := sqlx.Connect("pgx", "host=localhost user=development password=credential dbname=development sslmode=disable")
+	conn, err := sqlx.Connect("pgx", testDSN)
 	if err != nil {
 		log.Fatalf("connect errror: %v", err)
 	}
@@ -58,7 +29,10 @@ var pg = func() *postgre {
 		log.Fatal(err)
 	}
 
-	pg := New(conn)
+	pg, err := New(conn, WithEdgeHistory())
+	if err != nil {
+		log.Fatal(err)
+	}
 	return pg
 }()
 
@@ -70,22 +44,134 @@ func Test_postgredb(t *testing.T) {
 	t.Run("link iterator filter login logic", test_Link_iterator_Timefilter)
 
 	t.Run("edge upsert logic", test_upsert_edge)
+	t.Run("edge upsert caller time", test_upsert_edge_caller_time)
+	t.Run("bulk import", test_bulk_import)
+	t.Run("bulk import otelsql", test_bulk_import_otelsql)
+	t.Run("bulk import invalid url", test_bulk_import_invalid_url)
+	t.Run("url normalizer", test_url_normalizer)
+	t.Run("merge duplicate urls", test_merge_duplicate_urls)
+
+}
+
+func Test_graph_suite(t *testing.T) {
+	graphtest.Run(t, func(t *testing.T) linkgraph.Graph {
+		migrateDown(t)
+		migrateUp(t)
+		t.Cleanup(func() { migrateDown(t) })
+		return pg
+	})
+}
+
+func Test_graph_caller_edge_time(t *testing.T) {
+	graphtest.RunCallerEdgeTime(t, func(t *testing.T) linkgraph.Graph {
+		migrateDown(t)
+		migrateUp(t)
+		graph, err := New(pg.db, WithEdgeTimeSource(CallerTime))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { migrateDown(t) })
+		return graph
+	})
+}
+
+// run the suite again with tiny page so iterators cross page boundaries.
+func Test_graph_suite_paged(t *testing.T) {
+	graphtest.Run(t, func(t *testing.T) linkgraph.Graph {
+		migrateDown(t)
+		graph, err := New(pg.db, WithPageSize(2), WithEdgeHistory())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { migrateDown(t) })
+		return graph
+	})
+}
+
+func Test_graph_name_based_ids(t *testing.T) {
+	namespace := uuid.New()
+	graphtest.RunNameBasedIDs(t, namespace, func(t *testing.T) linkgraph.Graph {
+		migrateDown(t)
+		graph, err := New(pg.db, WithEdgeHistory(), WithNameBasedIDs(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { migrateDown(t) })
+		return graph
+	})
+}
+
+func Test_migrate(t *testing.T) {
+	migrateDown(t)
+	defer migrateDown(t)
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := migrations[len(migrations)-1].version
+
+	// concurrent replicas must end up with the same schema
+	var wg sync.WaitGroup
+	errC := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errC <- pg.Migrate(context.TODO())
+		}()
+	}
+	wg.Wait()
+	close(errC)
+	for err := range errC {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	version, err := pg.SchemaVersion(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != latest {
+		t.Fatalf("\ngot:%v\nexpect:%v", version, latest)
+	}
+
+	// every down migration revert its up migration
+	for i := len(migrations) - 2; i >= 0; i-- {
+		if err := pg.MigrateTo(context.TODO(), migrations[i].version); err != nil {
+			t.Fatal(err)
+		}
+		if err := pg.MigrateTo(context.TODO(), migrations[i+1].version); err != nil {
+			t.Fatal(err)
+		}
+		if err := pg.MigrateTo(context.TODO(), migrations[i].version); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
 
+func migrateUp(t *testing.T) {
+	if err := pg.Migrate(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func migrateDown(t *testing.T) {
+	if err := pg.MigrateTo(context.TODO(), 0); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func test_upsert_edge(t *testing.T) {
-	pg.db.ExecContext(context.TODO(), linkTable.Create)
-	pg.db.ExecContext(context.TODO(), edgeTable.Create)
-	defer func() {
-		pg.db.ExecContext(context.TODO(), edgeTable.Drop)
-		pg.db.ExecContext(context.TODO(), linkTable.Drop)
-	}()
+	migrateUp(t)
+	defer migrateDown(t)
 
 	// Create links
 	linkUUIDs := make([]uuid.UUID, 3)
 	for i := 0; i < 3; i++ {
 		link := &linkgraph.Link{URL: fmt.Sprint(i)}
-		err := pg.UpsertLink(link)
+		err := pg.UpsertLink(context.TODO(), link)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -97,7 +183,7 @@ func test_upsert_edge(t *testing.T) {
 		Dst: linkUUIDs[1],
 	}
 
-	err := pg.UpsertEdge(&original)
+	err := pg.UpsertEdge(context.TODO(), &original)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -118,7 +204,7 @@ func test_upsert_edge(t *testing.T) {
 		Dst: linkUUIDs[1],
 	}
 
-	err = pg.UpsertEdge(other)
+	err = pg.UpsertEdge(context.TODO(), other)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -140,7 +226,7 @@ func test_upsert_edge(t *testing.T) {
 		Dst: uuid.New(),
 	}
 
-	err = pg.UpsertEdge(unkwn)
+	err = pg.UpsertEdge(context.TODO(), unkwn)
 	if err != nil {
 		if err != linkgraph.ErrUnknownEdgeLinks {
 			t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrUnknownEdgeLinks)
@@ -148,13 +234,54 @@ func test_upsert_edge(t *testing.T) {
 	}
 }
 
+func test_upsert_edge_caller_time(t *testing.T) {
+	migrateUp(t)
+	defer migrateDown(t)
+
+	graph, err := New(pg.db, WithEdgeTimeSource(CallerTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := &linkgraph.Link{URL: "https://example.com/src"}
+	dst := &linkgraph.Link{URL: "https://example.com/dst"}
+	if err := graph.UpsertLinks(context.TODO(), []*linkgraph.Link{src, dst}); err != nil {
+		t.Fatal(err)
+	}
+
+	// replay of historical edge keep the supplied timestamp
+	updateAt := time.Now().Add(-24 * time.Hour).Truncate(time.Second).UTC()
+	edge := &linkgraph.Edge{Src: src.ID, Dst: dst.ID, UpdateAt: updateAt}
+	if err := graph.UpsertEdge(context.TODO(), edge); err != nil {
+		t.Fatal(err)
+	}
+	if !edge.UpdateAt.Equal(updateAt) {
+		t.Fatalf("\ngot:%v\nExpect:%v", edge.UpdateAt, updateAt)
+	}
+
+	// older replay must not regress freshness
+	older := &linkgraph.Edge{Src: src.ID, Dst: dst.ID, UpdateAt: updateAt.Add(-time.Hour)}
+	if err := graph.UpsertEdges(context.TODO(), []*linkgraph.Edge{older}); err != nil {
+		t.Fatal(err)
+	}
+	if !older.UpdateAt.Equal(updateAt) {
+		t.Fatalf("\ngot:%v\nExpect:%v\nmessage:%v", older.UpdateAt, updateAt,
+			"edge update_at moved backward")
+	}
+
+	// edge without timestamp is stamped by the store clock
+	fresh := &linkgraph.Edge{Src: dst.ID, Dst: src.ID}
+	if err := graph.UpsertEdge(context.TODO(), fresh); err != nil {
+		t.Fatal(err)
+	}
+	if !fresh.UpdateAt.After(updateAt) {
+		t.Fatalf("\ngot:%v\nmessage:%v", fresh.UpdateAt, "edge update_at not stamped")
+	}
+}
+
 func test_upsert_link(t *testing.T) {
-	pg.db.ExecContext(context.TODO(), linkTable.Create)
-	pg.db.ExecContext(context.TODO(), edgeTable.Create)
-	defer func() {
-		pg.db.ExecContext(context.TODO(), edgeTable.Drop)
-		pg.db.ExecContext(context.TODO(), linkTable.Drop)
-	}()
+	migrateUp(t)
+	defer migrateDown(t)
 
 	//=======================
 	// Create a new link
@@ -162,7 +289,7 @@ func test_upsert_link(t *testing.T) {
 		URL:         "https://example.com",
 		RetrievedAt: time.Now().Add(-10 * time.Hour),
 	}
-	err := pg.UpsertLink(original)
+	err := pg.UpsertLink(context.TODO(), original)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -175,7 +302,7 @@ func test_upsert_link(t *testing.T) {
 		URL:         "https://example.com",
 		RetrievedAt: accessedAt,
 	}
-	err = pg.UpsertLink(existing)
+	err = pg.UpsertLink(context.TODO(), existing)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -184,7 +311,7 @@ func test_upsert_link(t *testing.T) {
 			"value of ID should same")
 	}
 
-	stored, err := pg.LookupLink(existing.ID)
+	stored, err := pg.LookupLink(context.TODO(), existing.ID)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -200,7 +327,7 @@ func test_upsert_link(t *testing.T) {
 		URL:         existing.URL,
 		RetrievedAt: time.Now().Add(-10 * time.Hour).UTC(),
 	}
-	err = pg.UpsertLink(sameURL)
+	err = pg.UpsertLink(context.TODO(), sameURL)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -209,7 +336,7 @@ func test_upsert_link(t *testing.T) {
 			"value of ID should same")
 	}
 
-	stored, err = pg.LookupLink(existing.ID)
+	stored, err = pg.LookupLink(context.TODO(), existing.ID)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -225,7 +352,7 @@ func test_upsert_link(t *testing.T) {
 	dup := &linkgraph.Link{
 		URL: "foo",
 	}
-	err = pg.UpsertLink(dup)
+	err = pg.UpsertLink(context.TODO(), dup)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -242,12 +369,8 @@ func test_upsert_link(t *testing.T) {
 }
 
 func test_lookup_link(t *testing.T) {
-	pg.db.ExecContext(context.TODO(), linkTable.Create)
-	pg.db.ExecContext(context.TODO(), edgeTable.Create)
-	defer func() {
-		pg.db.ExecContext(context.TODO(), edgeTable.Drop)
-		pg.db.ExecContext(context.TODO(), linkTable.Drop)
-	}()
+	migrateUp(t)
+	defer migrateDown(t)
 
 	//====================================
 	// Create a new link
@@ -256,7 +379,7 @@ func test_lookup_link(t *testing.T) {
 		RetrievedAt: time.Now().Truncate(time.Second).UTC(),
 	}
 
-	err := pg.UpsertLink(link)
+	err := pg.UpsertLink(context.TODO(), link)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -266,7 +389,7 @@ func test_lookup_link(t *testing.T) {
 	}
 
 	// Lookup link by ID
-	other, err := pg.LookupLink(link.ID)
+	other, err := pg.LookupLink(context.TODO(), link.ID)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -276,7 +399,7 @@ func test_lookup_link(t *testing.T) {
 	}
 
 	// Lookup link by unknown ID
-	_, err = pg.LookupLink(uuid.Nil)
+	_, err = pg.LookupLink(context.TODO(), uuid.Nil)
 	if err != nil {
 		if err != linkgraph.ErrNotFound {
 			t.Fatalf("error should %v, got: %v", linkgraph.ErrNotFound, err)
@@ -286,12 +409,8 @@ func test_lookup_link(t *testing.T) {
 }
 
 func test_concurrent_link_iterators(t *testing.T) {
-	pg.db.ExecContext(context.TODO(), linkTable.Create)
-	pg.db.ExecContext(context.TODO(), edgeTable.Create)
-	defer func() {
-		pg.db.ExecContext(context.TODO(), edgeTable.Drop)
-		pg.db.ExecContext(context.TODO(), linkTable.Drop)
-	}()
+	migrateUp(t)
+	defer migrateDown(t)
 
 	// testing
 	var (
@@ -302,7 +421,7 @@ func test_concurrent_link_iterators(t *testing.T) {
 
 	for i := 0; i < numLinks; i++ {
 		l := linkgraph.Link{URL: fmt.Sprint(i)}
-		err := pg.UpsertLink(&l)
+		err := pg.UpsertLink(context.TODO(), &l)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -374,12 +493,8 @@ func test_concurrent_link_iterators(t *testing.T) {
 }
 
 func test_Link_iterator_Timefilter(t *testing.T) {
-	pg.db.ExecContext(context.TODO(), linkTable.Create)
-	pg.db.ExecContext(context.TODO(), edgeTable.Create)
-	defer func() {
-		pg.db.ExecContext(context.TODO(), edgeTable.Drop)
-		pg.db.ExecContext(context.TODO(), linkTable.Drop)
-	}()
+	migrateUp(t)
+	defer migrateDown(t)
 
 	//=======================
 	linkUUID := make([]uuid.UUID, 3)
@@ -387,7 +502,7 @@ func test_Link_iterator_Timefilter(t *testing.T) {
 
 	for i := 0; i < len(linkUUID); i++ {
 		link := &linkgraph.Link{URL: fmt.Sprint(i), RetrievedAt: time.Now()}
-		err := pg.UpsertLink(link)
+		err := pg.UpsertLink(context.TODO(), link)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -421,46 +536,256 @@ func test_Link_iterator_Timefilter(t *testing.T) {
 	}
 }
 
+func test_bulk_import(t *testing.T) {
+	migrateUp(t)
+	defer migrateDown(t)
+
+	existing := &linkgraph.Link{URL: "0", RetrievedAt: time.Now().Add(-time.Hour)}
+	if err := pg.UpsertLink(context.TODO(), existing); err != nil {
+		t.Fatal(err)
+	}
+
+	links := &linkSlice{links: []*linkgraph.Link{
+		{URL: "0", RetrievedAt: time.Now()},
+		{URL: "1", RetrievedAt: time.Now()},
+		{URL: "2", RetrievedAt: time.Now()},
+		{URL: "2", RetrievedAt: time.Now().Add(time.Minute)},
+	}}
+	edges := &edgeSlice{edges: []*BulkEdge{
+		{SrcURL: "0", DstURL: "1"},
+		{SrcURL: "0", DstURL: "1"},
+		{SrcURL: "1", DstURL: "2"},
+		{SrcURL: "1", DstURL: "unknown"},
+	}}
+
+	res, err := pg.BulkImport(context.TODO(), links, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := BulkResult{
+		LinksInserted: 2,
+		LinksUpdated:  1,
+		EdgesInserted: 2,
+		EdgesUpdated:  0,
+		EdgesSkipped:  2,
+	}
+	if *res != expected {
+		t.Fatalf("\ngot:%+v\nexpect:%+v", *res, expected)
+	}
+
+	// import again only update existing rows
+	links.curr, edges.curr = 0, 0
+	res, err = pg.BulkImport(context.TODO(), links, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.LinksInserted != 0 || res.LinksUpdated != 3 || res.EdgesInserted != 0 || res.EdgesUpdated != 2 {
+		t.Fatalf("\ngot:%+v", *res)
+	}
+}
+
+func test_bulk_import_invalid_url(t *testing.T) {
+	migrateUp(t)
+	defer migrateDown(t)
+
+	normalizer := linkgraph.NewNormalizer(linkgraph.MergeSchemes())
+	graph, err := New(pg.db, WithURLNormalizer(normalizer), WithNameBasedIDs(uuid.New()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the relative url would otherwise get a placeholder link
+	edges := &edgeSlice{edges: []*BulkEdge{
+		{SrcURL: "https://example.com/a", DstURL: "https://example.com/b"},
+		{SrcURL: "https://example.com/a", DstURL: "/relative"},
+	}}
+	res, err := graph.BulkImport(context.TODO(), nil, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.EdgesInserted != 1 || res.EdgesSkipped != 1 {
+		t.Fatalf("\ngot:%+v\nexpect: 1 inserted 1 skipped", *res)
+	}
+	if _, err := graph.LookupLinkByURL(context.TODO(), "/relative"); err == nil {
+		t.Fatal("expect no placeholder for invalid url")
+	}
+}
+
+// the service open the database through otelsqlx, BulkImport must unwrap its
+// driver connection.
+func test_bulk_import_otelsql(t *testing.T) {
+	migrateUp(t)
+	defer migrateDown(t)
+
+	db, err := otelsqlx.Connect("pgx", testDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	graph, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	links := &linkSlice{links: []*linkgraph.Link{
+		{URL: "0", RetrievedAt: time.Now()},
+		{URL: "1", RetrievedAt: time.Now()},
+	}}
+	edges := &edgeSlice{edges: []*BulkEdge{{SrcURL: "0", DstURL: "1"}}}
+
+	res, err := graph.BulkImport(context.TODO(), links, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.LinksInserted != 2 || res.EdgesInserted != 1 {
+		t.Fatalf("\ngot:%+v\nexpect: 2 links 1 edge inserted", *res)
+	}
+}
+
+type linkSlice struct {
+	links []*linkgraph.Link
+	curr  int
+}
+
+func (s *linkSlice) Next() bool            { s.curr++; return s.curr <= len(s.links) }
+func (s *linkSlice) Link() *linkgraph.Link { return s.links[s.curr-1] }
+func (s *linkSlice) Error() error          { return nil }
+
+type edgeSlice struct {
+	edges []*BulkEdge
+	curr  int
+}
+
+func (s *edgeSlice) Next() bool      { s.curr++; return s.curr <= len(s.edges) }
+func (s *edgeSlice) Edge() *BulkEdge { return s.edges[s.curr-1] }
+func (s *edgeSlice) Error() error    { return nil }
+
 func partitionLinkIter(pg linkgraph.Graph, t *testing.T, partition, numPartition int, accessBefore time.Time) (linkgraph.LinkIterator, error) {
-	from, to := partitionRange(t, partition, numPartition)
-	return pg.Links(from, to, accessBefore)
+	ranges, err := linkgraph.Partition(numPartition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := ranges[partition]
+	return pg.Links(context.TODO(), r.From, r.To, accessBefore)
 }
 
-func partitionRange(t *testing.T, partition, numPartition int) (from, to uuid.UUID) {
-	if partition < 0 || partition >= numPartition {
-		t.Fatal("invalid partition")
+func test_url_normalizer(t *testing.T) {
+	migrateUp(t)
+	defer migrateDown(t)
+
+	graph, err := New(pg.db, WithURLNormalizer(linkgraph.NewNormalizer(linkgraph.MergeSchemes(), linkgraph.StripTrailingSlash())))
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	var minUUID = uuid.Nil
-	var maxUUID = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
-	var err error
+	link := &linkgraph.Link{URL: "http://Example.com/a/"}
+	if err := graph.UpsertLink(context.TODO(), link); err != nil {
+		t.Fatal(err)
+	}
+	if link.URL != "https://example.com/a" || link.OriginalURL != "http://Example.com/a/" {
+		t.Fatalf("\ngot: %s %s", link.URL, link.OriginalURL)
+	}
 
-	//calculate size of each partition as(2^128/numPartition)
-	tokenRange := big.NewInt(0)
-	partSize := big.NewInt(0)
-	partSize.SetBytes(maxUUID[:])
-	partSize = partSize.Div(partSize, big.NewInt(int64(numPartition)))
+	// other spelling of the same url is the same link, the original url is
+	// the first one
+	batch := []*linkgraph.Link{{URL: "https://example.com/a#top"}, {URL: "not absolute"}}
+	err = graph.UpsertLinks(context.TODO(), batch)
+	var batchErr *linkgraph.BatchError
+	if !errors.As(err, &batchErr) || !errors.Is(batchErr.Errs[1], linkgraph.ErrInvalidURL) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrInvalidURL)
+	}
+	if batch[0].ID != link.ID || batch[0].OriginalURL != link.OriginalURL {
+		t.Fatalf("\ngot: %+v\nexpect: %+v", batch[0], link)
+	}
 
-	if partition == 0 {
-		from = minUUID
-	} else {
-		tokenRange.Mul(partSize, big.NewInt(int64(partition)))
-		from, err = uuid.FromBytes(tokenRange.Bytes())
-		if err != nil {
-			t.Fatal(err)
-		}
+	other, err := graph.LookupLinkByURL(context.TODO(), "HTTPS://example.com/a/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID != link.ID {
+		t.Fatalf("\ngot: %v\nexpect: %v", other.ID, link.ID)
 	}
+}
 
-	if partition == numPartition-1 {
-		to = maxUUID
-	} else {
-		tokenRange.Mul(partSize, big.NewInt(int64(partition+1)))
-		to, err = uuid.FromBytes(tokenRange.Bytes())
-		if err != nil {
+func test_merge_duplicate_urls(t *testing.T) {
+	migrateUp(t)
+	defer migrateDown(t)
+
+	// links stored before the normalizer was configured
+	now := time.Now().UTC()
+	links := []*linkgraph.Link{
+		{URL: "http://Example.com/a/", RetrievedAt: now.Add(-time.Hour)},
+		{URL: "https://example.com/a", RetrievedAt: now},
+		{URL: "https://example.com/b/"},
+		{URL: "https://example.com/c"},
+	}
+	if err := pg.UpsertLinks(context.TODO(), links); err != nil {
+		t.Fatal(err)
+	}
+	for _, edge := range []*linkgraph.Edge{
+		{Src: links[0].ID, Dst: links[2].ID},
+		{Src: links[1].ID, Dst: links[2].ID},
+		{Src: links[3].ID, Dst: links[0].ID},
+		// become link to itself once merged
+		{Src: links[0].ID, Dst: links[1].ID},
+		{Src: links[1].ID, Dst: links[0].ID},
+	} {
+		if err := pg.UpsertEdge(context.TODO(), edge); err != nil {
 			t.Fatal(err)
 		}
 	}
 
-	return from, to
+	graph, err := New(pg.db, WithURLNormalizer(linkgraph.NewNormalizer(linkgraph.MergeSchemes(), linkgraph.StripTrailingSlash())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := graph.MergeDuplicateURLs(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Merged != 1 || res.Rewritten != 1 {
+		t.Fatalf("\ngot: %+v\nexpect: 1 merged, 1 rewritten", res)
+	}
+
+	// the latest retrieval survive
+	survivor, err := graph.LookupLinkByURL(context.TODO(), "https://example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if survivor.ID != links[1].ID {
+		t.Fatalf("\ngot: %v\nexpect: %v", survivor.ID, links[1].ID)
+	}
+	if _, err := graph.LookupLink(context.TODO(), links[0].ID); err != linkgraph.ErrNotFound {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, linkgraph.ErrNotFound)
+	}
+	rewritten, err := graph.LookupLink(context.TODO(), links[2].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewritten.URL != "https://example.com/b" || rewritten.OriginalURL != "https://example.com/b/" {
+		t.Fatalf("\ngot: %s %s", rewritten.URL, rewritten.OriginalURL)
+	}
 
+	// edges of the duplicate moved to the survivor
+	var got []string
+	it, err := graph.Edges(context.TODO(), uuid.Nil, linkgraph.MaxUUID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for it.Next() {
+		got = append(got, it.Edge().Src.String()+"->"+it.Edge().Dst.String())
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		links[1].ID.String() + "->" + links[2].ID.String(),
+		links[3].ID.String() + "->" + links[1].ID.String(),
+	}
+	sort.Strings(got)
+	sort.Strings(expected)
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Fatalf("\ngot: %v\nexpect: %v", got, expected)
+	}
 }
@@ -0,0 +1,145 @@
+package linkpostgre
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// URLMergeResult report the outcome of MergeDuplicateURLs.
+type URLMergeResult struct {
+	// Rewritten count links whose url was replaced by its normalized form.
+	Rewritten int64
+
+	// Merged count duplicate links that were merged into another link and
+	// removed.
+	Merged int64
+}
+
+// urlCandidate is a link whose url normalize to the same url as other links.
+type urlCandidate struct {
+	id          uuid.UUID
+	url         string
+	retrievedAt time.Time
+}
+
+// MergeDuplicateURLs apply the url normalizer to the links stored before it
+// was configured. links whose url normalize to the same url are merged into
+// the most recently retrieved one: the edges (and edge history) of the
+// duplicates are moved to it and the duplicates are removed. the surviving
+// link get the normalized url and keep the replaced url as original url.
+// link whose url can't be normalized is left as is.
+//
+// every url of the graph is held in memory while the duplicates are found,
+// and each group of duplicates is merged in its own transaction. it must not
+// run concurrently with writers, an upsert racing with the merge of its url
+// fail the merge.
+func (p *postgre) MergeDuplicateURLs(ctx context.Context) (*URLMergeResult, error) {
+	if p.normalizer == nil {
+		return nil, fmt.Errorf("merge duplicate urls: url normalizer is not configured")
+	}
+	if p.linkID != nil {
+		// the surviving link would keep the ID of its old url
+		return nil, fmt.Errorf("merge duplicate urls: not supported with name-based IDs")
+	}
+
+	groups, err := p.urlGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("merge duplicate urls: %v", err)
+	}
+
+	var res URLMergeResult
+	for normalized, group := range groups {
+		if len(group) == 1 && group[0].url == normalized {
+			continue
+		}
+
+		survivor, err := p.mergeURLs(ctx, normalized, group)
+		if err != nil {
+			return &res, fmt.Errorf("merge duplicate urls: %s: %v", normalized, err)
+		}
+		res.Merged += int64(len(group) - 1)
+		if survivor.url != normalized {
+			res.Rewritten++
+		}
+	}
+	return &res, nil
+}
+
+// urlGroups group every link by normalized url.
+func (p *postgre) urlGroups(ctx context.Context) (map[string][]urlCandidate, error) {
+	rows, err := p.db.QueryxContext(ctx, linkURLsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := make(map[string][]urlCandidate)
+	for rows.Next() {
+		var c urlCandidate
+		if err := rows.Scan(&c.id, &c.url, &c.retrievedAt); err != nil {
+			return nil, err
+		}
+
+		normalized, err := p.normalizer.Normalize(c.url)
+		if err != nil {
+			continue
+		}
+		groups[normalized] = append(groups[normalized], c)
+	}
+	return groups, rows.Err()
+}
+
+// mergeURLs merge group into a single link with the normalized url, it
+// return the link that survived.
+func (p *postgre) mergeURLs(ctx context.Context, normalized string, group []urlCandidate) (urlCandidate, error) {
+	survivor := 0
+	for i, c := range group[1:] {
+		if survivesMerge(c, group[survivor], normalized) {
+			survivor = i + 1
+		}
+	}
+
+	var dups []uuid.UUID
+	for i, c := range group {
+		if i != survivor {
+			dups = append(dups, c.id)
+		}
+	}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return urlCandidate{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if len(dups) > 0 {
+		for _, query := range []string{mergeURLEdgesQuery, mergeURLEdgeHistoryQuery, mergeURLRedirectsQuery} {
+			if _, err := tx.ExecContext(ctx, query, group[survivor].id, dups); err != nil {
+				return urlCandidate{}, err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, mergeURLRemoveQuery, dups); err != nil {
+			return urlCandidate{}, err
+		}
+	}
+	if group[survivor].url != normalized {
+		if _, err := tx.ExecContext(ctx, mergeURLRewriteQuery, group[survivor].id, normalized); err != nil {
+			return urlCandidate{}, err
+		}
+	}
+
+	return group[survivor], tx.Commit()
+}
+
+// survivesMerge report whether c should survive the merge instead of other,
+// the latest retrieval win, then the link that already has the normalized
+// url.
+func survivesMerge(c, other urlCandidate, normalized string) bool {
+	if !c.retrievedAt.Equal(other.retrievedAt) {
+		return c.retrievedAt.After(other.retrievedAt)
+	}
+	return c.url == normalized && other.url != normalized
+}
@@ -0,0 +1,187 @@
+package linkpostgre
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migrations hold the versioned schema, each version has a pair of file
+// named <version>_<name>.up.sql and <version>_<name>.down.sql.
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// migrationLockKey is the advisory lock key held while migrating, so
+// replicas that start at the same time don't apply the same migration twice.
+const migrationLockKey = 0x6c696e6b73746f72 // "linkstor"
+
+// migration is a single versioned schema change.
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// loadMigrations parse the embedded migration files sorted by version.
+func loadMigrations() ([]migration, error) {
+	files, err := fs.Glob(migrationFS, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, file := range files {
+		base := path.Base(file)
+
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: unknown direction", base)
+		}
+
+		versionStr, name, ok := strings.Cut(strings.TrimSuffix(base, "."+direction+".sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expect <version>_<name>", base)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %v", base, err)
+		}
+
+		content, err := migrationFS.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &migration{version: version, name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.up = string(content)
+		} else {
+			m.down = string(content)
+		}
+	}
+
+	list := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing up or down file", m.version, m.name)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].version < list[j].version })
+
+	return list, nil
+}
+
+// Migrate apply every pending migration.
+func (p *postgre) Migrate(ctx context.Context) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return fmt.Errorf("migrate: %v", err)
+	}
+	if len(migrations) == 0 {
+		return nil
+	}
+
+	return p.MigrateTo(ctx, migrations[len(migrations)-1].version)
+}
+
+// MigrateTo move the schema to the given version, applying up migrations if
+// the schema is older or down migrations if it is newer. version 0 revert
+// every migration.
+//
+// all migrations are applied within a single transaction that hold an
+// advisory lock, so concurrent callers are serialized and a failed migration
+// leave the schema untouched.
+func (p *postgre) MigrateTo(ctx context.Context, version int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return fmt.Errorf("migrate: %v", err)
+	}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrate: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, migrationLockQuery, migrationLockKey); err != nil {
+		return fmt.Errorf("migrate: lock: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, createMigrationTableQuery); err != nil {
+		return fmt.Errorf("migrate: %v", err)
+	}
+
+	current, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("migrate: %v", err)
+	}
+
+	if version >= current {
+		for _, m := range migrations {
+			if m.version <= current || m.version > version {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, m.up); err != nil {
+				return fmt.Errorf("migrate: up %d_%s: %v", m.version, m.name, err)
+			}
+			if _, err := tx.ExecContext(ctx, insertMigrationQuery, m.version, m.name); err != nil {
+				return fmt.Errorf("migrate: up %d_%s: %v", m.version, m.name, err)
+			}
+		}
+	} else {
+		for i := len(migrations) - 1; i >= 0; i-- {
+			m := migrations[i]
+			if m.version > current || m.version <= version {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, m.down); err != nil {
+				return fmt.Errorf("migrate: down %d_%s: %v", m.version, m.name, err)
+			}
+			if _, err := tx.ExecContext(ctx, deleteMigrationQuery, m.version); err != nil {
+				return fmt.Errorf("migrate: down %d_%s: %v", m.version, m.name, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migrate: %v", err)
+	}
+	return nil
+}
+
+// SchemaVersion return the latest applied migration, 0 if none.
+func (p *postgre) SchemaVersion(ctx context.Context) (int, error) {
+	if _, err := p.db.ExecContext(ctx, createMigrationTableQuery); err != nil {
+		return 0, fmt.Errorf("schema version: %v", err)
+	}
+
+	version, err := schemaVersion(ctx, p.db)
+	if err != nil {
+		return 0, fmt.Errorf("schema version: %v", err)
+	}
+	return version, nil
+}
+
+func schemaVersion(ctx context.Context, q sqlx.QueryerContext) (int, error) {
+	var version int
+	err := q.QueryRowxContext(ctx, schemaVersionQuery).Scan(&version)
+	return version, err
+}
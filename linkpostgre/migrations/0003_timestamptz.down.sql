@@ -0,0 +1,2 @@
+ALTER TABLE links ALTER COLUMN retrieved_at TYPE TIMESTAMP USING retrieved_at AT TIME ZONE 'UTC';
+ALTER TABLE edges ALTER COLUMN update_at TYPE TIMESTAMP USING update_at AT TIME ZONE 'UTC';
@@ -0,0 +1,3 @@
+-- existing values were written as UTC by the application
+ALTER TABLE links ALTER COLUMN retrieved_at TYPE TIMESTAMPTZ USING retrieved_at AT TIME ZONE 'UTC';
+ALTER TABLE edges ALTER COLUMN update_at TYPE TIMESTAMPTZ USING update_at AT TIME ZONE 'UTC';
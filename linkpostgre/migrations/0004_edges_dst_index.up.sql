@@ -0,0 +1,2 @@
+-- reverse index for InEdges and for removing the edges of a link
+CREATE INDEX IF NOT EXISTS edges_dst_idx ON edges(dst);
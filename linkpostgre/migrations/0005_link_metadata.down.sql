@@ -0,0 +1,8 @@
+ALTER TABLE links
+	DROP COLUMN IF EXISTS status_code,
+	DROP COLUMN IF EXISTS content_type,
+	DROP COLUMN IF EXISTS content_length,
+	DROP COLUMN IF EXISTS content_hash,
+	DROP COLUMN IF EXISTS title,
+	DROP COLUMN IF EXISTS last_modified,
+	DROP COLUMN IF EXISTS etag;
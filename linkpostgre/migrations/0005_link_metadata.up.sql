@@ -0,0 +1,9 @@
+-- metadata of the last retrieval, zero values mean unknown
+ALTER TABLE links
+	ADD COLUMN status_code INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN content_type TEXT NOT NULL DEFAULT '',
+	ADD COLUMN content_length BIGINT NOT NULL DEFAULT 0,
+	ADD COLUMN content_hash BYTEA,
+	ADD COLUMN title TEXT NOT NULL DEFAULT '',
+	ADD COLUMN last_modified TIMESTAMPTZ NOT NULL DEFAULT '0001-01-01 00:00:00+00',
+	ADD COLUMN etag TEXT NOT NULL DEFAULT '';
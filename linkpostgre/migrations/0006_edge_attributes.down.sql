@@ -0,0 +1,5 @@
+ALTER TABLE edges
+	DROP COLUMN IF EXISTS anchor_text,
+	DROP COLUMN IF EXISTS rel,
+	DROP COLUMN IF EXISTS boilerplate,
+	DROP COLUMN IF EXISTS occurrences;
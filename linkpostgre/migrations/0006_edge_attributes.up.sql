@@ -0,0 +1,6 @@
+-- rel is the bitmask of linkgraph.EdgeRel
+ALTER TABLE edges
+	ADD COLUMN anchor_text TEXT NOT NULL DEFAULT '',
+	ADD COLUMN rel SMALLINT NOT NULL DEFAULT 0,
+	ADD COLUMN boilerplate BOOLEAN NOT NULL DEFAULT FALSE,
+	ADD COLUMN occurrences INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS edge_history;
+ALTER TABLE edges DROP COLUMN IF EXISTS first_seen;
@@ -0,0 +1,19 @@
+ALTER TABLE edges ADD COLUMN first_seen TIMESTAMPTZ;
+UPDATE edges SET first_seen = update_at;
+
+-- edges removed by RemoveStaleEdges, each row is the [first_seen, vanished_at)
+-- interval the edge existed
+CREATE TABLE IF NOT EXISTS edge_history(
+	id UUID PRIMARY KEY,
+	src UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+	dst UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+	update_at TIMESTAMPTZ,
+	anchor_text TEXT NOT NULL DEFAULT '',
+	rel SMALLINT NOT NULL DEFAULT 0,
+	boilerplate BOOLEAN NOT NULL DEFAULT FALSE,
+	occurrences INTEGER NOT NULL DEFAULT 0,
+	first_seen TIMESTAMPTZ,
+	vanished_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS edge_history_src_dst_idx ON edge_history(src, dst);
+CREATE INDEX IF NOT EXISTS edge_history_dst_idx ON edge_history(dst);
@@ -0,0 +1,2 @@
+-- url before normalization, empty for link that was not normalized
+ALTER TABLE links ADD COLUMN original_url TEXT NOT NULL DEFAULT '';
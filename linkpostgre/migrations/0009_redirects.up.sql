@@ -0,0 +1,10 @@
+-- redirect of the alias link to the target link, an alias has at most one
+-- redirect
+CREATE TABLE IF NOT EXISTS redirects(
+	alias UUID PRIMARY KEY REFERENCES links(id) ON DELETE CASCADE,
+	target UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+	status_code INTEGER NOT NULL DEFAULT 0,
+	observed_at TIMESTAMPTZ NOT NULL,
+	CONSTRAINT redirect_not_self CHECK (alias <> target)
+);
+CREATE INDEX IF NOT EXISTS redirects_target_idx ON redirects(target);
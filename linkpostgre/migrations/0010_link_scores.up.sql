@@ -0,0 +1,2 @@
+-- scores computed over the graph, written by UpdateScores only
+ALTER TABLE links ADD COLUMN pagerank DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE links ADD COLUMN trustrank DOUBLE PRECISION NOT NULL DEFAULT 0;
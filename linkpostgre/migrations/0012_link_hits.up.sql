@@ -0,0 +1,2 @@
+ALTER TABLE links ADD COLUMN hub DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE links ADD COLUMN authority DOUBLE PRECISION NOT NULL DEFAULT 0;
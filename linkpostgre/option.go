@@ -0,0 +1,114 @@
+package linkpostgre
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// TimeSource select where the timestamp of a write come from, see
+// linkgraph.TimeSource.
+type TimeSource = linkgraph.TimeSource
+
+const (
+	CallerTime = linkgraph.CallerTime
+	StoreTime  = linkgraph.StoreTime
+)
+
+// defaultPageSize is the number of rows fetched per page by iterators.
+const defaultPageSize = 1000
+
+// Option configure the postgres graph.
+type Option func(*postgre)
+
+// WithClock set the store clock, it default to time.Now. the store clock is
+// the only source of time the graph use on its own, the database NOW() is
+// never used so every timestamp follow the same clock.
+func WithClock(clock func() time.Time) Option {
+	return func(p *postgre) {
+		p.clock = clock
+	}
+}
+
+// WithTimeSource select where link retrieved_at come from, it default to
+// CallerTime.
+func WithTimeSource(src TimeSource) Option {
+	return func(p *postgre) {
+		p.linkTime = src
+	}
+}
+
+// WithEdgeTimeSource select where edge update_at come from, it default to
+// StoreTime. CallerTime is meant for replaying crawl logs or backfilling
+// historical data, edge with zero UpdateAt is still stamped by the store
+// clock. either way update_at never move backward.
+func WithEdgeTimeSource(src TimeSource) Option {
+	return func(p *postgre) {
+		p.edgeTime = src
+	}
+}
+
+// WithPageSize set the number of rows Links and Edges iterators fetch per
+// round trip, it bound the memory used by a single iterator. non-positive
+// size is ignored.
+func WithPageSize(size int) Option {
+	return func(p *postgre) {
+		if size > 0 {
+			p.pageSize = size
+		}
+	}
+}
+
+// WithEdgeHistory make RemoveStaleEdges move the removed edges to the
+// edge_history table instead of deleting them, so Edges with linkgraph.AsOf
+// can return the edges that vanished since.
+func WithEdgeHistory() Option {
+	return func(p *postgre) {
+		p.edgeHistory = true
+	}
+}
+
+// WithURLNormalizer normalize url of links before they are stored or looked
+// up, the url supplied by the caller is kept as linkgraph.Link.OriginalURL.
+// see MergeDuplicateURLs for graph that already contain links.
+func WithURLNormalizer(n *linkgraph.Normalizer) Option {
+	return func(p *postgre) {
+		p.normalizer = n
+	}
+}
+
+// WithNameBasedIDs assign link ID derived from the (normalized) url under
+// namespace instead of random ID, see linkgraph.NameBasedID. edges may then be
+// upserted before the links they reference.
+//
+// it is meant for new graph, link stored with random ID can't be upserted
+// anymore.
+func WithNameBasedIDs(namespace uuid.UUID) Option {
+	return func(p *postgre) {
+		p.linkID = func(url string) uuid.UUID {
+			return linkgraph.NameBasedID(namespace, url)
+		}
+	}
+}
+
+// now return the store clock in UTC.
+func (p *postgre) now() time.Time {
+	return p.clock().UTC()
+}
+
+// linkRetrievedAt apply the time source to timestamp supplied by caller.
+func (p *postgre) linkRetrievedAt(retrievedAt time.Time) time.Time {
+	if p.linkTime == StoreTime {
+		return p.now()
+	}
+	return retrievedAt
+}
+
+// edgeUpdateAt apply the edge time source to timestamp supplied by caller.
+func (p *postgre) edgeUpdateAt(updateAt time.Time) time.Time {
+	if p.edgeTime == CallerTime && !updateAt.IsZero() {
+		return updateAt
+	}
+	return p.now()
+}
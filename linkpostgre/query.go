@@ -1,56 +1,554 @@
 package linkpostgre
 
-const createLinkTableQuery = `
-		CREATE TABLE IF NOT EXISTS links(
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			url text UNIQUE,
-			retrieved_at TIMESTAMP 
-		);
+// the tables schema live in migrations directory.
+
+const createMigrationTableQuery = `
+	CREATE TABLE IF NOT EXISTS schema_migrations(
+		version INTEGER PRIMARY KEY,
+		name text NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+`
+
+// lock is released when the transaction ends.
+const migrationLockQuery = `
+	SELECT pg_advisory_xact_lock($1)
+`
+
+const schemaVersionQuery = `
+	SELECT COALESCE(MAX(version), 0)
+	FROM schema_migrations
+`
+
+const insertMigrationQuery = `
+	INSERT INTO schema_migrations (version, name)
+	VALUES ($1, $2)
 `
 
-const createEdgeTableQuery = `
-		CREATE TABLE IF NOT EXISTS edges(
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			src UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
-			dst UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
-			update_at TIMESTAMP,
-			CONSTRAINT edge_links UNIQUE(src,dst)
-		);
+const deleteMigrationQuery = `
+	DELETE FROM schema_migrations
+	WHERE version = $1
 `
 
+// linkColumns is the column list read by scanLink. placeholder link has no
+// url.
+const linkColumns = `id, COALESCE(url, '') AS url, original_url, retrieved_at, status_code, content_type, content_length, content_hash, title, last_modified, etag, pagerank, trustrank, hub, authority`
+
+// linkIsNewerRetrieval hold when the inserted link carry a retrieval at least
+// as recent as the stored one. link that is only discovered carry the zero
+// retrieved_at and never qualify.
+const linkIsNewerRetrieval = `(EXCLUDED.retrieved_at >= links.retrieved_at AND EXCLUDED.retrieved_at > '0001-01-01 00:00:00+00')`
+
+// linkMergeSet merge inserted link into the existing one, retrieved_at only
+// move forward and the metadata is taken from the newer retrieval. the
+// original url is the first one known.
+const linkMergeSet = `
+		original_url=CASE WHEN links.original_url = '' THEN EXCLUDED.original_url ELSE links.original_url END,
+		retrieved_at=GREATEST(links.retrieved_at, EXCLUDED.retrieved_at),
+		status_code=CASE WHEN ` + linkIsNewerRetrieval + ` THEN EXCLUDED.status_code ELSE links.status_code END,
+		content_type=CASE WHEN ` + linkIsNewerRetrieval + ` THEN EXCLUDED.content_type ELSE links.content_type END,
+		content_length=CASE WHEN ` + linkIsNewerRetrieval + ` THEN EXCLUDED.content_length ELSE links.content_length END,
+		content_hash=CASE WHEN ` + linkIsNewerRetrieval + ` THEN EXCLUDED.content_hash ELSE links.content_hash END,
+		title=CASE WHEN ` + linkIsNewerRetrieval + ` THEN EXCLUDED.title ELSE links.title END,
+		last_modified=CASE WHEN ` + linkIsNewerRetrieval + ` THEN EXCLUDED.last_modified ELSE links.last_modified END,
+		etag=CASE WHEN ` + linkIsNewerRetrieval + ` THEN EXCLUDED.etag ELSE links.etag END
+`
+
+// linkUpsertConflict merge link with the same url.
+const linkUpsertConflict = `
+	ON CONFLICT (url) DO UPDATE SET` + linkMergeSet
+
+// linkUpsertByIDConflict merge link with the same name-based ID, the existing
+// link may be a placeholder that has no url yet.
+const linkUpsertByIDConflict = `
+	ON CONFLICT (id) DO UPDATE SET
+		url=EXCLUDED.url,` + linkMergeSet
+
 const lookupLinkQuery = `
-	SELECT id, url, retrieved_at
+	SELECT ` + linkColumns + `
 	FROM links
 	WHERE id = $1
 `
 
+const lookupLinkByURLQuery = `
+	SELECT ` + linkColumns + `
+	FROM links
+	WHERE url = $1
+`
+
+const resolveURLsQuery = `
+	SELECT id, url
+	FROM links
+	WHERE url = ANY($1)
+`
+
+// edgeColumns is the column list read by scanEdge, live edge has not
+// vanished.
+const edgeColumns = `id, src, dst, update_at, anchor_text, rel, boilerplate, occurrences, first_seen, NULL::timestamptz AS vanished_at`
+
+// edgeHistoryColumns is edgeColumns of edge_history.
+const edgeHistoryColumns = `id, src, dst, update_at, anchor_text, rel, boilerplate, occurrences, first_seen, vanished_at`
+
+// edgeUpsertConflict refresh update_at of existing edge, the attributes are
+// taken from the upsert unless it is older than the stored edge. first_seen
+// only move backward, e.g. when older crawl is replayed.
+const edgeUpsertConflict = `
+	ON CONFLICT (src,dst) DO UPDATE SET
+		update_at=GREATEST(edges.update_at, EXCLUDED.update_at),
+		first_seen=LEAST(edges.first_seen, EXCLUDED.first_seen),
+		anchor_text=CASE WHEN EXCLUDED.update_at >= edges.update_at THEN EXCLUDED.anchor_text ELSE edges.anchor_text END,
+		rel=CASE WHEN EXCLUDED.update_at >= edges.update_at THEN EXCLUDED.rel ELSE edges.rel END,
+		boilerplate=CASE WHEN EXCLUDED.update_at >= edges.update_at THEN EXCLUDED.boilerplate ELSE edges.boilerplate END,
+		occurrences=CASE WHEN EXCLUDED.update_at >= edges.update_at THEN EXCLUDED.occurrences ELSE edges.occurrences END
+`
+
+const lookupEdgeQuery = `
+	SELECT ` + edgeColumns + `
+	FROM edges
+	WHERE id = $1
+`
+
+// edges of removed links are removed by ON DELETE CASCADE. link that is not
+// retrieved yet has the zero time passed as $2.
+const linkRemoveQuery = `
+	DELETE FROM links
+	WHERE id = $1
+`
+
+const linkRemoveStaleQuery = `
+	DELETE FROM links
+	WHERE retrieved_at < $1 AND retrieved_at <> $2
+`
+
+const linkRemoveOrphanQuery = `
+	DELETE FROM links l
+	WHERE retrieved_at < $1 AND retrieved_at <> $2
+		AND NOT EXISTS (SELECT 1 FROM edges WHERE src = l.id)
+		AND NOT EXISTS (SELECT 1 FROM edges WHERE dst = l.id)
+`
+
 const edgeRemoveStaleQuery = `
 	DELETE FROM edges 
 	WHERE src=$1 and update_at < $2
+	RETURNING dst
 `
 
-const edgeUpsertQuery = `
-	INSERT INTO edges (src, dst, update_at) 
-	VALUES ($1, $2, NOW())
-	ON CONFLICT (src,dst) DO UPDATE SET update_at=NOW()
-	RETURNING id,update_at
+// edgeRemoveStaleQuery that move the removed edges to edge_history, they
+// vanished at $2.
+const edgeRemoveStaleHistoryQuery = `
+	WITH removed AS (
+		DELETE FROM edges
+		WHERE src=$1 and update_at < $2
+		RETURNING id, src, dst, update_at, anchor_text, rel, boilerplate, occurrences, first_seen
+	), history AS (
+		INSERT INTO edge_history (` + edgeHistoryColumns + `)
+		SELECT removed.*, $2::timestamptz FROM removed
+	)
+	SELECT dst FROM removed
 `
 
+// dry run of edgeRemoveStaleQuery
+const edgeSelectStaleQuery = `
+	SELECT dst FROM edges
+	WHERE src=$1 and update_at < $2
+`
+
+const edgeUpsertQuery = `
+	INSERT INTO edges (src, dst, update_at, first_seen, anchor_text, rel, boilerplate, occurrences) 
+	VALUES ($1, $2, $3, $3, $4, $5, $6, $7)
+	` + edgeUpsertConflict + `
+	RETURNING ` + edgeColumns
+
 const linkUpsertQuery = `
-	INSERT INTO links (url, retrieved_at) 
-	VALUES ($1, $2)
-	ON CONFLICT (url) DO UPDATE SET retrieved_at=GREATEST(links.retrieved_at, $2)
-	RETURNING id,retrieved_at
+	INSERT INTO links (url, retrieved_at, status_code, content_type, content_length, content_hash, title, last_modified, etag, original_url) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	` + linkUpsertConflict + `
+	RETURNING ` + linkColumns
+
+// name-based ID variant of linkUpsertQuery, $11 is the ID.
+const linkUpsertByIDQuery = `
+	INSERT INTO links (url, retrieved_at, status_code, content_type, content_length, content_hash, title, last_modified, etag, original_url, id) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	` + linkUpsertByIDConflict + `
+	RETURNING ` + linkColumns
+
+// batch variant of linkUpsertQuery, the urls must be unique within the batch
+// because a row can't be updated twice by the same statement.
+const linkBatchUpsertQuery = `
+	INSERT INTO links (url, retrieved_at, status_code, content_type, content_length, content_hash, title, last_modified, etag, original_url)
+	SELECT * FROM unnest($1::text[], $2::timestamptz[], $3::integer[], $4::text[], $5::bigint[], $6::bytea[], $7::text[], $8::timestamptz[], $9::text[], $10::text[])
+	` + linkUpsertConflict + `
+	RETURNING ` + linkColumns
+
+// name-based ID variant of linkBatchUpsertQuery, $11 is the IDs.
+const linkBatchUpsertByIDQuery = `
+	INSERT INTO links (url, retrieved_at, status_code, content_type, content_length, content_hash, title, last_modified, etag, original_url, id)
+	SELECT * FROM unnest($1::text[], $2::timestamptz[], $3::integer[], $4::text[], $5::bigint[], $6::bytea[], $7::text[], $8::timestamptz[], $9::text[], $10::text[], $11::uuid[])
+	` + linkUpsertByIDConflict + `
+	RETURNING ` + linkColumns
+
+// placeholder for the unknown name-based IDs in $1, it has no url and was
+// never retrieved.
+const linkPlaceholderQuery = `
+	INSERT INTO links (id, retrieved_at)
+	SELECT id, '0001-01-01 00:00:00+00' FROM unnest($1::uuid[]) AS placeholder(id)
+	ON CONFLICT (id) DO NOTHING
+`
+
+// lock the existing links so they can't be removed before the edges that
+// reference them are inserted.
+const linkExistQuery = `
+	SELECT id
+	FROM links
+	WHERE id = ANY($1)
+	FOR KEY SHARE
+`
+
+// batch variant of edgeUpsertQuery, the (src,dst) pairs must be unique within
+// the batch.
+const edgeBatchUpsertQuery = `
+	INSERT INTO edges (src, dst, update_at, first_seen, anchor_text, rel, boilerplate, occurrences)
+	SELECT src, dst, update_at, update_at, anchor_text, rel, boilerplate, occurrences
+	FROM unnest($1::uuid[], $2::uuid[], $3::timestamptz[], $4::text[], $5::smallint[], $6::boolean[], $7::integer[])
+		AS batch(src, dst, update_at, anchor_text, rel, boilerplate, occurrences)
+	` + edgeUpsertConflict + `
+	RETURNING ` + edgeColumns
+
+// relNofollow is linkgraph.RelNofollow as sql literal.
+const relNofollow = `1`
+
+// edges are paginated by the (src,dst) unique index, next page start right
+// after the last edge of previous page. nofollow edges are skipped when
+// requested.
+const edgesFirstPageQuery = `
+	SELECT ` + edgeColumns + `
+	FROM edges
+	WHERE src >= $1 AND src <= $2 AND update_at < $3 AND (NOT $4::boolean OR rel & ` + relNofollow + ` = 0)
+	ORDER BY src, dst
+	LIMIT $5
+`
+
+const edgesNextPageQuery = `
+	SELECT ` + edgeColumns + `
+	FROM edges
+	WHERE (src, dst) > ($1, $2) AND src <= $3 AND update_at < $4 AND (NOT $5::boolean OR rel & ` + relNofollow + ` = 0)
+	ORDER BY src, dst
+	LIMIT $6
+`
+
+// as-of variant of edgesFirstPageQuery and edgesNextPageQuery, $3 (or $4)
+// is the as-of time. live edges and edge_history are merged and only one edge
+// is kept per (src,dst) so the keyset stay unique, the live edge win.
+const edgesAsOfFirstPageQuery = `
+	SELECT DISTINCT ON (src, dst) *
+	FROM (
+		SELECT ` + edgeColumns + `
+		FROM edges
+		WHERE src >= $1 AND src <= $2 AND first_seen <= $3
+		UNION ALL
+		SELECT ` + edgeHistoryColumns + `
+		FROM edge_history
+		WHERE src >= $1 AND src <= $2 AND first_seen <= $3 AND vanished_at > $3
+	) asof
+	WHERE NOT $4::boolean OR rel & ` + relNofollow + ` = 0
+	ORDER BY src, dst, vanished_at DESC NULLS FIRST
+	LIMIT $5
+`
+
+const edgesAsOfNextPageQuery = `
+	SELECT DISTINCT ON (src, dst) *
+	FROM (
+		SELECT ` + edgeColumns + `
+		FROM edges
+		WHERE (src, dst) > ($1, $2) AND src <= $3 AND first_seen <= $4
+		UNION ALL
+		SELECT ` + edgeHistoryColumns + `
+		FROM edge_history
+		WHERE (src, dst) > ($1, $2) AND src <= $3 AND first_seen <= $4 AND vanished_at > $4
+	) asof
+	WHERE NOT $5::boolean OR rel & ` + relNofollow + ` = 0
+	ORDER BY src, dst, vanished_at DESC NULLS FIRST
+	LIMIT $6
+`
+
+// out edges are paginated by the (src,dst) unique index, in edges by the dst
+// index.
+const outEdgesFirstPageQuery = `
+	SELECT ` + edgeColumns + `
+	FROM edges
+	WHERE src = $1
+	ORDER BY dst
+	LIMIT $2
 `
 
-const edgesIterationQuery = `
-	SELECT id, src, dst, update_at 
-	FROM edges 
-	WHERE src >= $1 AND src < $2 AND update_at < $3
+const outEdgesNextPageQuery = `
+	SELECT ` + edgeColumns + `
+	FROM edges
+	WHERE dst > $1 AND src = $2
+	ORDER BY dst
+	LIMIT $3
+`
+
+const inEdgesFirstPageQuery = `
+	SELECT ` + edgeColumns + `
+	FROM edges
+	WHERE dst = $1
+	ORDER BY src
+	LIMIT $2
+`
+
+const inEdgesNextPageQuery = `
+	SELECT ` + edgeColumns + `
+	FROM edges
+	WHERE src > $1 AND dst = $2
+	ORDER BY src
+	LIMIT $3
+`
+
+// links are paginated by primary key. the upper bound of Links and Edges
+// range is passed inclusive, see linkgraph.Range.Last.
+const linksFirstPageQuery = `
+	SELECT ` + linkColumns + `
+	FROM links 
+	WHERE id >= $1 AND id <= $2 AND retrieved_at < $3
+	ORDER BY id
+	LIMIT $4
 `
 
-const linksIterationQuery = `
-	SELECT id, url, retrieved_at 
+const linksNextPageQuery = `
+	SELECT ` + linkColumns + `
 	FROM links 
-	WHERE id >= $1 AND id < $2 AND retrieved_at < $3
-	`
+	WHERE id > $1 AND id <= $2 AND retrieved_at < $3
+	ORDER BY id
+	LIMIT $4
+`
+
+//========== scores
+
+// scoreUpdateQuery set the score column %s of the links $1 to $2.
+const scoreUpdateQuery = `
+	UPDATE links SET %s = score.value
+	FROM unnest($1::uuid[], $2::double precision[]) AS score(id, value)
+	WHERE links.id = score.id
+`
+
+//========== redirects
+
+const redirectColumns = `alias, target, status_code, observed_at`
+
+// older observation does not replace the stored redirect.
+const redirectUpsertConflict = `
+	ON CONFLICT (alias) DO UPDATE SET
+		target=EXCLUDED.target,
+		status_code=EXCLUDED.status_code,
+		observed_at=EXCLUDED.observed_at
+	WHERE redirects.observed_at <= EXCLUDED.observed_at`
+
+// the stored redirect is returned when it is more recent, the upsert then
+// return no row.
+const redirectUpsertQuery = `
+	WITH upserted AS (
+		INSERT INTO redirects (alias, target, status_code, observed_at)
+		VALUES ($1, $2, $3, $4)
+		` + redirectUpsertConflict + `
+		RETURNING ` + redirectColumns + `
+	)
+	SELECT ` + redirectColumns + ` FROM upserted
+	UNION ALL
+	SELECT ` + redirectColumns + ` FROM redirects
+	WHERE alias = $1 AND NOT EXISTS (SELECT 1 FROM upserted)
+`
+
+// redirectChainsQuery follow the redirects of every alias in $1, each row is
+// a hop of the chain that start at the alias. the hop that come back to a
+// link of the chain has loop set and end the walk.
+const redirectChainsQuery = `
+	WITH RECURSIVE chain(start, depth, visited, loop, alias, target, status_code, observed_at) AS (
+		SELECT alias, 1, ARRAY[alias, target], false, ` + redirectColumns + `
+		FROM redirects
+		WHERE alias = ANY($1::uuid[])
+		UNION ALL
+		SELECT c.start, c.depth + 1, c.visited || r.target, r.target = ANY(c.visited), r.alias, r.target, r.status_code, r.observed_at
+		FROM chain c
+		JOIN redirects r ON r.alias = c.target
+		WHERE NOT c.loop
+	)
+	SELECT start, loop, ` + redirectColumns + `
+	FROM chain
+	ORDER BY start, depth
+`
+
+//========== bulk import
+
+const createLinkStagingQuery = `
+	CREATE TEMP TABLE links_staging(
+		url text,
+		retrieved_at TIMESTAMPTZ,
+		status_code INTEGER,
+		content_type TEXT,
+		content_length BIGINT,
+		content_hash BYTEA,
+		title TEXT,
+		last_modified TIMESTAMPTZ,
+		etag TEXT,
+		original_url TEXT,
+		id UUID
+	) ON COMMIT DROP;
+`
+
+// duplicate url are collapsed to their latest retrieval, xmax = 0 only
+// holds for row inserted by this statement.
+const mergeLinkStagingQuery = `
+	WITH merged AS (
+		INSERT INTO links (url, retrieved_at, status_code, content_type, content_length, content_hash, title, last_modified, etag, original_url)
+		SELECT DISTINCT ON (url) url, retrieved_at, status_code, content_type, content_length, content_hash, title, last_modified, etag, original_url
+		FROM links_staging
+		ORDER BY url, retrieved_at DESC
+		` + linkUpsertConflict + `
+		RETURNING (xmax = 0) AS inserted
+	)
+	SELECT COUNT(*) FILTER (WHERE inserted), COUNT(*) FILTER (WHERE NOT inserted)
+	FROM merged
+`
+
+// name-based ID variant of mergeLinkStagingQuery, the IDs are computed by
+// the import.
+const mergeLinkStagingByIDQuery = `
+	WITH merged AS (
+		INSERT INTO links (url, retrieved_at, status_code, content_type, content_length, content_hash, title, last_modified, etag, original_url, id)
+		SELECT DISTINCT ON (id) url, retrieved_at, status_code, content_type, content_length, content_hash, title, last_modified, etag, original_url, id
+		FROM links_staging
+		ORDER BY id, retrieved_at DESC
+		` + linkUpsertByIDConflict + `
+		RETURNING (xmax = 0) AS inserted
+	)
+	SELECT COUNT(*) FILTER (WHERE inserted), COUNT(*) FILTER (WHERE NOT inserted)
+	FROM merged
+`
+
+const createEdgeStagingQuery = `
+	CREATE TEMP TABLE edges_staging(
+		src_url text,
+		dst_url text,
+		src_id UUID,
+		dst_id UUID
+	) ON COMMIT DROP;
+`
+
+// edges that reference unknown url are dropped by the join. bulk edges carry
+// no attribute, existing edge keep its attributes.
+const mergeEdgeStagingQuery = `
+	WITH merged AS (
+		INSERT INTO edges (src, dst, update_at, first_seen)
+		SELECT DISTINCT src.id, dst.id, $1::timestamptz, $1::timestamptz
+		FROM edges_staging staging
+		JOIN links src ON src.url = staging.src_url
+		JOIN links dst ON dst.url = staging.dst_url
+		ON CONFLICT (src,dst) DO UPDATE SET
+			update_at=GREATEST(edges.update_at, EXCLUDED.update_at),
+			first_seen=LEAST(edges.first_seen, EXCLUDED.first_seen)
+		RETURNING (xmax = 0) AS inserted
+	)
+	SELECT COUNT(*) FILTER (WHERE inserted), COUNT(*) FILTER (WHERE NOT inserted)
+	FROM merged
+`
+
+// name-based ID variant of mergeEdgeStagingQuery, edges are not resolved by
+// url, the unknown endpoints are created beforehand by
+// edgeStagingPlaceholderQuery.
+const mergeEdgeStagingByIDQuery = `
+	WITH merged AS (
+		INSERT INTO edges (src, dst, update_at, first_seen)
+		SELECT DISTINCT src_id, dst_id, $1::timestamptz, $1::timestamptz
+		FROM edges_staging
+		ON CONFLICT (src,dst) DO UPDATE SET
+			update_at=GREATEST(edges.update_at, EXCLUDED.update_at),
+			first_seen=LEAST(edges.first_seen, EXCLUDED.first_seen)
+		RETURNING (xmax = 0) AS inserted
+	)
+	SELECT COUNT(*) FILTER (WHERE inserted), COUNT(*) FILTER (WHERE NOT inserted)
+	FROM merged
+`
+
+const edgeStagingPlaceholderQuery = `
+	INSERT INTO links (id, retrieved_at)
+	SELECT src_id, '0001-01-01 00:00:00+00'::timestamptz FROM edges_staging
+	UNION
+	SELECT dst_id, '0001-01-01 00:00:00+00'::timestamptz FROM edges_staging
+	ON CONFLICT (id) DO NOTHING
+`
+
+//========== url merge
+
+const linkURLsQuery = `
+	SELECT id, url, retrieved_at
+	FROM links
+	WHERE url IS NOT NULL
+`
+
+// edges of the duplicates $2 are moved to the link $1, edges that end up with
+// the same (src,dst) are merged like UpsertEdge. edge between the link and a
+// duplicate would become a link to itself, it is dropped.
+const mergeURLEdgesQuery = `
+	INSERT INTO edges (src, dst, update_at, first_seen, anchor_text, rel, boilerplate, occurrences)
+	SELECT DISTINCT ON (src, dst)
+		src, dst, update_at, MIN(first_seen) OVER (PARTITION BY src, dst), anchor_text, rel, boilerplate, occurrences
+	FROM (
+		SELECT
+			CASE WHEN src = ANY($2::uuid[]) THEN $1::uuid ELSE src END AS src,
+			CASE WHEN dst = ANY($2::uuid[]) THEN $1::uuid ELSE dst END AS dst,
+			update_at, first_seen, anchor_text, rel, boilerplate, occurrences
+		FROM edges
+		WHERE src = ANY($2::uuid[]) OR dst = ANY($2::uuid[])
+	) moved
+	WHERE src <> dst
+	ORDER BY src, dst, update_at DESC
+	` + edgeUpsertConflict
+
+// history of the duplicates $2 is moved to the link $1 like mergeURLEdgesQuery,
+// the rows that would become link to itself are deleted instead.
+const mergeURLEdgeHistoryQuery = `
+	WITH dropped AS (
+		DELETE FROM edge_history
+		WHERE (src = ANY($2::uuid[]) OR dst = ANY($2::uuid[]))
+			AND (src = $1::uuid OR src = ANY($2::uuid[]))
+			AND (dst = $1::uuid OR dst = ANY($2::uuid[]))
+	)
+	UPDATE edge_history SET
+		src=CASE WHEN src = ANY($2::uuid[]) THEN $1::uuid ELSE src END,
+		dst=CASE WHEN dst = ANY($2::uuid[]) THEN $1::uuid ELSE dst END
+	WHERE (src = ANY($2::uuid[]) OR dst = ANY($2::uuid[]))
+		AND NOT ((src = $1::uuid OR src = ANY($2::uuid[])) AND (dst = $1::uuid OR dst = ANY($2::uuid[])))
+`
+
+// redirects of the duplicates $2 are moved to the link $1, redirect of the
+// link to itself is dropped.
+const mergeURLRedirectsQuery = `
+	INSERT INTO redirects (` + redirectColumns + `)
+	SELECT DISTINCT ON (alias) ` + redirectColumns + `
+	FROM (
+		SELECT
+			CASE WHEN alias = ANY($2::uuid[]) THEN $1::uuid ELSE alias END AS alias,
+			CASE WHEN target = ANY($2::uuid[]) THEN $1::uuid ELSE target END AS target,
+			status_code, observed_at
+		FROM redirects
+		WHERE alias = ANY($2::uuid[]) OR target = ANY($2::uuid[])
+	) moved
+	WHERE alias <> target
+	ORDER BY alias, observed_at DESC
+	` + redirectUpsertConflict
+
+// the remaining edges and redirects of the duplicates are removed by ON
+// DELETE CASCADE.
+const mergeURLRemoveQuery = `
+	DELETE FROM links
+	WHERE id = ANY($1::uuid[])
+`
+
+// the url being replaced become the original url unless it is already known.
+const mergeURLRewriteQuery = `
+	UPDATE links SET
+		url=$2,
+		original_url=CASE WHEN original_url = '' THEN url ELSE original_url END
+	WHERE id = $1
+`
@@ -0,0 +1,127 @@
+package linkpostgre
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// UpsertRedirect implements graph.Graph.
+func (p *postgre) UpsertRedirect(ctx context.Context, redirect *linkgraph.Redirect) error {
+	if redirect.Alias == redirect.Target {
+		return linkgraph.ErrRedirectLoop
+	}
+
+	observedAt := redirect.ObservedAt
+	if observedAt.IsZero() {
+		observedAt = p.now()
+	}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("upsert redirect: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if p.linkID != nil {
+		if _, err := tx.ExecContext(ctx, linkPlaceholderQuery, []uuid.UUID{redirect.Alias, redirect.Target}); err != nil {
+			return fmt.Errorf("upsert redirect: %v", err)
+		}
+	}
+
+	stored, err := scanRedirect(tx.QueryRowxContext(ctx, redirectUpsertQuery,
+		redirect.Alias,
+		redirect.Target,
+		redirect.StatusCode,
+		observedAt,
+	))
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return linkgraph.ErrUnknownEdgeLinks
+		}
+		return fmt.Errorf("upsert redirect: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("upsert redirect: %v", err)
+	}
+
+	*redirect = *stored
+	return nil
+}
+
+// ResolveRedirect implements graph.Graph.
+func (p *postgre) ResolveRedirect(ctx context.Context, linkID uuid.UUID) ([]*linkgraph.Redirect, error) {
+	chains, err := p.redirectChains(ctx, []uuid.UUID{linkID})
+	if err != nil {
+		return nil, fmt.Errorf("resolve redirect: %v", err)
+	}
+
+	chain := chains[linkID]
+	if chain.loop {
+		return nil, linkgraph.ErrRedirectLoop
+	}
+	return chain.hops, nil
+}
+
+// redirectChain is the chain of redirects that start at a link.
+type redirectChain struct {
+	hops []*linkgraph.Redirect
+	loop bool
+}
+
+// target return the final target of the chain, or false when the link does
+// not redirect or its redirects loop.
+func (c redirectChain) target() (uuid.UUID, bool) {
+	if c.loop || len(c.hops) == 0 {
+		return uuid.Nil, false
+	}
+	return c.hops[len(c.hops)-1].Target, true
+}
+
+// redirectChains follow the redirects of every link in ids, link that does
+// not redirect is omitted.
+func (p *postgre) redirectChains(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]redirectChain, error) {
+	rows, err := p.db.QueryxContext(ctx, redirectChainsQuery, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chains := make(map[uuid.UUID]redirectChain)
+	for rows.Next() {
+		var (
+			start uuid.UUID
+			loop  bool
+			r     linkgraph.Redirect
+		)
+		if err := rows.Scan(&start, &loop, &r.Alias, &r.Target, &r.StatusCode, &r.ObservedAt); err != nil {
+			return nil, err
+		}
+		r.ObservedAt = r.ObservedAt.UTC()
+
+		chain := chains[start]
+		chain.hops = append(chain.hops, &r)
+		chain.loop = chain.loop || loop
+		chains[start] = chain
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return chains, nil
+}
+
+// scanRedirect read redirect row selected as redirectColumns.
+func scanRedirect(row scanner) (*linkgraph.Redirect, error) {
+	var r linkgraph.Redirect
+	if err := row.Scan(&r.Alias, &r.Target, &r.StatusCode, &r.ObservedAt); err != nil {
+		return nil, err
+	}
+
+	r.ObservedAt = r.ObservedAt.UTC()
+	return &r, nil
+}
@@ -0,0 +1,40 @@
+package linkpostgre
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+// scoreColumns map every linkgraph.ScoreKind to its column of links.
+var scoreColumns = map[linkgraph.ScoreKind]string{
+	linkgraph.ScorePageRank:  "pagerank",
+	linkgraph.ScoreTrustRank: "trustrank",
+	linkgraph.ScoreHub:       "hub",
+	linkgraph.ScoreAuthority: "authority",
+}
+
+// UpdateScores implements graph.Graph.
+func (p *postgre) UpdateScores(ctx context.Context, kind linkgraph.ScoreKind, scores map[uuid.UUID]float64) error {
+	column, ok := scoreColumns[kind]
+	if !ok {
+		return linkgraph.ErrUnknownScoreKind
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(scores))
+	values := make([]float64, 0, len(scores))
+	for id, score := range scores {
+		ids = append(ids, id)
+		values = append(values, score)
+	}
+
+	if _, err := p.db.ExecContext(ctx, fmt.Sprintf(scoreUpdateQuery, column), ids, values); err != nil {
+		return fmt.Errorf("update scores: %v", err)
+	}
+	return nil
+}
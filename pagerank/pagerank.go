@@ -0,0 +1,419 @@
+// Package pagerank compute the PageRank of the links of a linkgraph.Graph and
+// store it back as linkgraph.ScorePageRank. with a teleport vector biased
+// toward a set of seeds it compute the personalized PageRank instead, e.g.
+// the TrustRank of the links.
+//
+// the graph is loaded in memory with partitioned Links and Edges scans, then
+// the scores are computed by power iteration where every partition of the
+// links is updated by its own goroutine:
+//
+//	c := pagerank.New(graph, pagerank.WithPartitions(8))
+//	res, err := c.Run(ctx)
+package pagerank
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/internal/graphsnap"
+	"github.com/odit-bit/linkstore/linkgraph"
+)
+
+const (
+	defaultDampingFactor = 0.85
+	defaultTolerance     = 1e-6
+	defaultMaxIterations = 100
+	defaultBatchSize     = 1000
+)
+
+// Calculator compute the PageRank of a graph, it can be run any number of
+// time.
+type Calculator struct {
+	g linkgraph.Graph
+
+	dampingFactor float64
+	tolerance     float64
+	maxIterations int
+	partitions    int
+	batchSize     int
+	edgeOpts      []linkgraph.EdgeOption
+	teleport      Teleport
+	kind          linkgraph.ScoreKind
+	progress      func(Progress)
+}
+
+// Option configure Calculator.
+type Option func(*Calculator)
+
+// WithDampingFactor set the probability to follow a link instead of jumping
+// to a random one, it default to 0.85.
+func WithDampingFactor(d float64) Option {
+	return func(c *Calculator) {
+		c.dampingFactor = d
+	}
+}
+
+// WithTolerance set the convergence threshold, the iteration stop once the
+// scores change by less than tolerance in total (L1 norm). it default to
+// 1e-6.
+func WithTolerance(tolerance float64) Option {
+	return func(c *Calculator) {
+		c.tolerance = tolerance
+	}
+}
+
+// WithMaxIterations bound the number of iterations when the scores don't
+// converge, it default to 100.
+func WithMaxIterations(n int) Option {
+	return func(c *Calculator) {
+		c.maxIterations = n
+	}
+}
+
+// WithPartitions set the number of uuid partitions scanned and updated in
+// parallel, it default to the number of CPU.
+func WithPartitions(n int) Option {
+	return func(c *Calculator) {
+		c.partitions = n
+	}
+}
+
+// WithBatchSize set the number of scores stored per UpdateScores call, it
+// default to 1000.
+func WithBatchSize(n int) Option {
+	return func(c *Calculator) {
+		c.batchSize = n
+	}
+}
+
+// WithEdgeOptions replace the options of the Edges scan, by default nofollow
+// edges are skipped and redirects are resolved (linkgraph.SkipNofollow and
+// linkgraph.ResolveRedirects).
+func WithEdgeOptions(opts ...linkgraph.EdgeOption) Option {
+	return func(c *Calculator) {
+		c.edgeOpts = opts
+	}
+}
+
+// WithTeleport set the teleport vector, it default to Uniform.
+func WithTeleport(t Teleport) Option {
+	return func(c *Calculator) {
+		c.teleport = t
+	}
+}
+
+// WithScoreKind set the score stored by Run, it default to
+// linkgraph.ScorePageRank.
+func WithScoreKind(kind linkgraph.ScoreKind) Option {
+	return func(c *Calculator) {
+		c.kind = kind
+	}
+}
+
+// WithProgress call fn as the computation advance, fn is never called
+// concurrently.
+func WithProgress(fn func(Progress)) Option {
+	return func(c *Calculator) {
+		c.progress = fn
+	}
+}
+
+// New return calculator for the links of g.
+func New(g linkgraph.Graph, opts ...Option) *Calculator {
+	c := Calculator{
+		g:             g,
+		dampingFactor: defaultDampingFactor,
+		tolerance:     defaultTolerance,
+		maxIterations: defaultMaxIterations,
+		partitions:    runtime.NumCPU(),
+		batchSize:     defaultBatchSize,
+		edgeOpts:      []linkgraph.EdgeOption{linkgraph.SkipNofollow(), linkgraph.ResolveRedirects()},
+		kind:          linkgraph.ScorePageRank,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// TrustRank return calculator of the trust of the links of g: the PageRank
+// personalized to the trusted seeds, stored as linkgraph.ScoreTrustRank.
+// trust flow from the seeds along the edges, so spam that is not linked from
+// trusted links get no trust. opts are applied after.
+func TrustRank(g linkgraph.Graph, seeds []uuid.UUID, opts ...Option) *Calculator {
+	return New(g, append([]Option{WithTeleport(Seeds(seeds...)), WithScoreKind(linkgraph.ScoreTrustRank)}, opts...)...)
+}
+
+// Phase of the computation.
+type Phase string
+
+const (
+	PhaseLoading   Phase = "loading"
+	PhaseIterating Phase = "iterating"
+	PhaseStoring   Phase = "storing"
+	PhaseDone      Phase = "done"
+)
+
+// Progress report how far the computation is.
+type Progress struct {
+	Phase Phase
+
+	// size of the loaded graph, zero while loading
+	Links int
+	Edges int
+
+	// number of iterations done and the change of the scores in the last one
+	Iterations int
+	Delta      float64
+
+	// number of scores stored by Run
+	Stored int
+}
+
+// Result of a computation.
+type Result struct {
+	// PageRank of every link, the scores sum to 1
+	Scores map[uuid.UUID]float64
+
+	// number of links and (distinct) edges of the graph
+	Links int
+	Edges int
+
+	// number of iterations run and whether the scores converged before
+	// reaching the maximum
+	Iterations int
+	Converged  bool
+
+	// change of the scores (L1 norm) in the last iteration
+	Delta float64
+}
+
+// Run compute the PageRank of every link and store it in the graph, the
+// scores of links added after the graph was loaded are left untouched.
+func (c *Calculator) Run(ctx context.Context) (*Result, error) {
+	if c.batchSize < 1 {
+		return nil, fmt.Errorf("pagerank: invalid batch size %d", c.batchSize)
+	}
+	if !c.kind.Valid() {
+		return nil, fmt.Errorf("pagerank: %w", linkgraph.ErrUnknownScoreKind)
+	}
+
+	res, err := c.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := res.progress(PhaseStoring)
+	c.report(progress)
+	if err := c.store(ctx, res.Scores, &progress); err != nil {
+		return nil, fmt.Errorf("pagerank: store scores: %v", err)
+	}
+
+	progress.Phase = PhaseDone
+	c.report(progress)
+	return res, nil
+}
+
+// Compute compute the PageRank of every link without storing it.
+func (c *Calculator) Compute(ctx context.Context) (*Result, error) {
+	res, err := c.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.report(res.progress(PhaseDone))
+	return res, nil
+}
+
+func (c *Calculator) compute(ctx context.Context) (*Result, error) {
+	if c.dampingFactor <= 0 || c.dampingFactor >= 1 {
+		return nil, fmt.Errorf("pagerank: damping factor %v is not in (0, 1)", c.dampingFactor)
+	}
+
+	ranges, err := linkgraph.Partition(c.partitions)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: %v", err)
+	}
+
+	c.report(Progress{Phase: PhaseLoading})
+	s, err := graphsnap.Load(ctx, c.g, ranges, c.edgeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: load graph: %w", err)
+	}
+
+	teleport, err := teleportVector(c.teleport, s)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: %w", err)
+	}
+
+	res := Result{Links: s.Len(), Edges: s.Edges()}
+	c.report(res.progress(PhaseIterating))
+	scores, err := c.iterate(ctx, s, teleport, &res)
+	if err != nil {
+		return nil, fmt.Errorf("pagerank: %w", err)
+	}
+
+	res.Scores = make(map[uuid.UUID]float64, s.Len())
+	for v, id := range s.IDs {
+		res.Scores[id] = scores[v]
+	}
+	return &res, nil
+}
+
+// iterate run the power iteration, the scores start as the teleport vector
+// (nil for uniform). the score of dangling link (link without out edge) is
+// spread like the teleport so the scores keep summing to 1.
+func (c *Calculator) iterate(ctx context.Context, s *graphsnap.Snapshot, teleport []float64, res *Result) ([]float64, error) {
+	n := s.Len()
+	if n == 0 {
+		res.Converged = true
+		return nil, nil
+	}
+
+	var (
+		d     = c.dampingFactor
+		curr  = make([]float64, n)
+		next  = make([]float64, n)
+		share = make([]float64, n)
+
+		// per partition sums, reduced after every pass
+		dangling = make([]float64, s.Partitions())
+		delta    = make([]float64, s.Partitions())
+	)
+	for v := range curr {
+		curr[v] = jump(teleport, v, n)
+	}
+
+	for res.Iterations < c.maxIterations {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// share of the score sent along every out edge
+		s.Parallel(func(p, from, to int) {
+			dangling[p] = 0
+			for v := from; v < to; v++ {
+				if s.OutDegree[v] == 0 {
+					dangling[p] += curr[v]
+					continue
+				}
+				share[v] = curr[v] / float64(s.OutDegree[v])
+			}
+		})
+
+		// probability to jump instead of following an edge
+		jumping := (1 - d) + d*graphsnap.Sum(dangling)
+		s.Parallel(func(p, from, to int) {
+			delta[p] = 0
+			for v := from; v < to; v++ {
+				var in float64
+				for _, u := range s.In[s.InStart[v]:s.InStart[v+1]] {
+					in += share[u]
+				}
+				next[v] = jumping*jump(teleport, v, n) + d*in
+				delta[p] += math.Abs(next[v] - curr[v])
+			}
+		})
+
+		curr, next = next, curr
+		res.Iterations++
+		res.Delta = graphsnap.Sum(delta)
+		c.report(res.progress(PhaseIterating))
+		if res.Delta < c.tolerance {
+			res.Converged = true
+			break
+		}
+	}
+
+	return curr, nil
+}
+
+// jump return the probability to jump to link v of n.
+func jump(teleport []float64, v, n int) float64 {
+	if teleport == nil {
+		return 1 / float64(n)
+	}
+	return teleport[v]
+}
+
+func (res *Result) progress(phase Phase) Progress {
+	return Progress{
+		Phase:      phase,
+		Links:      res.Links,
+		Edges:      res.Edges,
+		Iterations: res.Iterations,
+		Delta:      res.Delta,
+	}
+}
+
+func (c *Calculator) report(p Progress) {
+	if c.progress != nil {
+		c.progress(p)
+	}
+}
+
+// store write scores in batches, the batches are sent concurrently. the
+// number of stored scores is reported in progress.
+func (c *Calculator) store(ctx context.Context, scores map[uuid.UUID]float64, progress *Progress) error {
+	batches := make(chan map[uuid.UUID]float64)
+	errC := make(chan error, c.partitions)
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for i := 0; i < c.partitions; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if err := c.g.UpdateScores(ctx, c.kind, batch); err != nil {
+					errC <- err
+					return
+				}
+
+				mu.Lock()
+				progress.Stored += len(batch)
+				c.report(*progress)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var err error
+	batch := make(map[uuid.UUID]float64, c.batchSize)
+	send := func() bool {
+		select {
+		case batches <- batch:
+			batch = make(map[uuid.UUID]float64, c.batchSize)
+			return true
+		case err = <-errC:
+			return false
+		}
+	}
+	for id, score := range scores {
+		batch[id] = score
+		if len(batch) >= c.batchSize && !send() {
+			break
+		}
+	}
+	if err == nil && len(batch) > 0 {
+		send()
+	}
+	close(batches)
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	select {
+	case err = <-errC:
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,330 @@
+package pagerank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/linkgraph"
+	"github.com/odit-bit/linkstore/linkgraph/memory"
+)
+
+func Test_pagerank(t *testing.T) {
+	t.Run("cycle", test_pagerank_cycle)
+	t.Run("dangling links", test_pagerank_dangling)
+	t.Run("partitions agree", test_pagerank_partitions)
+	t.Run("edge options", test_pagerank_edge_options)
+	t.Run("store scores", test_pagerank_store)
+	t.Run("personalized", test_pagerank_personalized)
+	t.Run("trustrank", test_pagerank_trustrank)
+	t.Run("progress", test_pagerank_progress)
+	t.Run("invalid config", test_pagerank_invalid)
+	t.Run("cancelled context", test_pagerank_cancelled)
+}
+
+func test_pagerank_cycle(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+	ids := seedGraph(ctx, t, g, 3, [][2]int{{0, 1}, {1, 2}, {2, 0}})
+
+	res, err := New(g).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Converged || res.Links != 3 || res.Edges != 3 {
+		t.Fatalf("\ngot: %+v", res)
+	}
+	for _, id := range ids {
+		assertScore(t, res.Scores[id], 1.0/3)
+	}
+}
+
+func test_pagerank_dangling(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+
+	// 3 has no out edge, 4 has no in edge, the edge 0 -> 1 is duplicated by
+	// a second upsert and 2 -> 2 is a self link, both count once or not at all
+	edges := [][2]int{{0, 1}, {0, 2}, {1, 2}, {2, 0}, {2, 3}, {4, 2}, {2, 2}}
+	ids := seedGraph(ctx, t, g, 5, edges)
+
+	res, err := New(g, WithTolerance(1e-12), WithMaxIterations(1000)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Edges != 6 {
+		t.Fatalf("\ngot: %v edges\nexpect: %v", res.Edges, 6)
+	}
+
+	expect := referencePageRank(5, [][2]int{{0, 1}, {0, 2}, {1, 2}, {2, 0}, {2, 3}, {4, 2}}, 0.85, nil)
+	var total float64
+	for i, id := range ids {
+		assertScore(t, res.Scores[id], expect[i])
+		total += res.Scores[id]
+	}
+	assertScore(t, total, 1)
+}
+
+func test_pagerank_partitions(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+
+	rnd := rand.New(rand.NewSource(1))
+	var edges [][2]int
+	for i := 0; i < 1000; i++ {
+		edges = append(edges, [2]int{rnd.Intn(200), rnd.Intn(200)})
+	}
+	ids := seedGraph(ctx, t, g, 200, edges)
+
+	single, err := New(g, WithPartitions(1), WithTolerance(1e-12)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := New(g, WithPartitions(7), WithTolerance(1e-12)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if single.Iterations != parallel.Iterations || single.Edges != parallel.Edges {
+		t.Fatalf("\ngot: %+v\nexpect: %+v", parallel, single)
+	}
+	for _, id := range ids {
+		assertScore(t, parallel.Scores[id], single.Scores[id])
+	}
+}
+
+func test_pagerank_edge_options(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+	ids := seedGraph(ctx, t, g, 4, nil)
+
+	// 0 -> 1 is nofollow, 2 -> 3 end at alias of 1
+	for _, edge := range []*linkgraph.Edge{
+		{Src: ids[0], Dst: ids[1], Rel: linkgraph.RelNofollow},
+		{Src: ids[2], Dst: ids[3]},
+	} {
+		if err := g.UpsertEdge(ctx, edge); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.UpsertRedirect(ctx, &linkgraph.Redirect{Alias: ids[3], Target: ids[1], StatusCode: 301}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := New(g, WithTolerance(1e-12)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := referencePageRank(4, [][2]int{{2, 1}}, 0.85, nil)
+	for i, id := range ids {
+		assertScore(t, res.Scores[id], expect[i])
+	}
+
+	// every edge as is
+	res, err = New(g, WithTolerance(1e-12), WithEdgeOptions()).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = referencePageRank(4, [][2]int{{0, 1}, {2, 3}}, 0.85, nil)
+	for i, id := range ids {
+		assertScore(t, res.Scores[id], expect[i])
+	}
+}
+
+func test_pagerank_store(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+	ids := seedGraph(ctx, t, g, 50, [][2]int{{0, 1}, {1, 2}, {2, 0}, {3, 0}})
+
+	res, err := New(g, WithBatchSize(7)).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		link, err := g.LookupLink(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if link.PageRank != res.Scores[id] || link.PageRank == 0 {
+			t.Fatalf("\ngot: %v\nexpect: %v", link.PageRank, res.Scores[id])
+		}
+	}
+}
+
+func test_pagerank_personalized(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+	edges := [][2]int{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {4, 0}}
+	ids := seedGraph(ctx, t, g, 5, edges)
+
+	weights := map[uuid.UUID]float64{ids[1]: 3, ids[4]: 1, uuid.New(): 5}
+	res, err := New(g, WithTeleport(Weighted(weights)), WithTolerance(1e-12), WithPartitions(3)).Compute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := referencePageRank(5, edges, 0.85, []float64{0, 0.75, 0, 0, 0.25})
+	for i, id := range ids {
+		assertScore(t, res.Scores[id], expect[i])
+	}
+
+	// seeds that are not part of the graph
+	if _, err := New(g, WithTeleport(Seeds(uuid.New()))).Compute(ctx); !errors.Is(err, ErrNoSeeds) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, ErrNoSeeds)
+	}
+}
+
+func test_pagerank_trustrank(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+
+	// 0,1,2 are trusted pages that link to 3, the spam farm 4,5,6 link to
+	// each other and to 3
+	ids := seedGraph(ctx, t, g, 7, [][2]int{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {4, 5}, {5, 6}, {6, 4}, {4, 3}, {5, 3}})
+
+	res, err := TrustRank(g, []uuid.UUID{ids[0]}).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range ids {
+		link, err := g.LookupLink(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if link.TrustRank != res.Scores[id] || link.PageRank != 0 {
+			t.Fatalf("\ngot: trust %v pagerank %v\nexpect: trust %v", link.TrustRank, link.PageRank, res.Scores[id])
+		}
+		if spam := i >= 4; spam != (link.TrustRank == 0) {
+			t.Fatalf("link %d\ngot: trust %v", i, link.TrustRank)
+		}
+	}
+}
+
+func test_pagerank_progress(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+	seedGraph(ctx, t, g, 20, [][2]int{{0, 1}, {1, 2}, {2, 0}, {3, 1}})
+
+	var reports []Progress
+	res, err := New(g, WithBatchSize(3), WithPartitions(4), WithProgress(func(p Progress) {
+		reports = append(reports, p)
+	})).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	phases := []Phase{PhaseLoading, PhaseIterating, PhaseStoring, PhaseDone}
+	phase := 0
+	for _, p := range reports {
+		for phase < len(phases) && phases[phase] != p.Phase {
+			phase++
+		}
+		if phase == len(phases) {
+			t.Fatalf("phase %q out of order in %v", p.Phase, reports)
+		}
+	}
+
+	last := reports[len(reports)-1]
+	if last.Phase != PhaseDone || last.Stored != 20 || last.Iterations != res.Iterations || last.Links != 20 {
+		t.Fatalf("\ngot: %+v\nexpect: done with 20 stored after %d iterations", last, res.Iterations)
+	}
+}
+
+func test_pagerank_invalid(t *testing.T) {
+	ctx := context.Background()
+	g := memory.New()
+
+	for _, opt := range []Option{WithDampingFactor(1), WithDampingFactor(0), WithPartitions(0), WithBatchSize(0), WithScoreKind(0)} {
+		if _, err := New(g, opt).Run(ctx); err == nil {
+			t.Fatal("expect error")
+		}
+	}
+
+	// empty graph has nothing to rank
+	res, err := New(g).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Converged || len(res.Scores) != 0 {
+		t.Fatalf("\ngot: %+v", res)
+	}
+}
+
+func test_pagerank_cancelled(t *testing.T) {
+	g := memory.New()
+	seedGraph(context.Background(), t, g, 3, [][2]int{{0, 1}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := New(g).Compute(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("\ngot: %v\nexpect: %v", err, context.Canceled)
+	}
+}
+
+// seedGraph create n links and the edges between them, it return the link IDs
+// in creation order.
+func seedGraph(ctx context.Context, t *testing.T, g linkgraph.Graph, n int, edges [][2]int) []uuid.UUID {
+	t.Helper()
+
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		link := &linkgraph.Link{URL: fmt.Sprintf("https://example.com/%d", i)}
+		if err := g.UpsertLink(ctx, link); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = link.ID
+	}
+	for _, e := range edges {
+		if err := g.UpsertEdge(ctx, &linkgraph.Edge{Src: ids[e[0]], Dst: ids[e[1]]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ids
+}
+
+// referencePageRank is the textbook sequential power iteration over distinct
+// edges without self link, nil teleport is uniform.
+func referencePageRank(n int, edges [][2]int, d float64, teleport []float64) []float64 {
+	if teleport == nil {
+		teleport = make([]float64, n)
+		for i := range teleport {
+			teleport[i] = 1 / float64(n)
+		}
+	}
+
+	out := make([]int, n)
+	for _, e := range edges {
+		out[e[0]]++
+	}
+
+	pr := append([]float64(nil), teleport...)
+	for iter := 0; iter < 10000; iter++ {
+		var dangling float64
+		for i := range pr {
+			if out[i] == 0 {
+				dangling += pr[i]
+			}
+		}
+
+		next := make([]float64, n)
+		for i := range next {
+			next[i] = ((1 - d) + d*dangling) * teleport[i]
+		}
+		for _, e := range edges {
+			next[e[1]] += d * pr[e[0]] / float64(out[e[0]])
+		}
+		pr = next
+	}
+	return pr
+}
+
+func assertScore(t *testing.T, got, expect float64) {
+	t.Helper()
+	if math.Abs(got-expect) > 1e-9 {
+		t.Fatalf("\ngot: %v\nexpect: %v", got, expect)
+	}
+}
@@ -0,0 +1,69 @@
+package pagerank
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/internal/graphsnap"
+)
+
+// ErrNoSeeds is returned when the teleport vector has no weight on any link
+// of the graph, e.g. seeds that are all unknown.
+var ErrNoSeeds = errors.New("teleport vector has no link of the graph")
+
+// Teleport return the weight of jumping to every link of ids, the weights
+// only need to be proportional and link with zero weight is never jumped to.
+// ids is sorted. the random surfer also jump according to the teleport
+// vector when it reach a link without out edge.
+//
+// nil Teleport is Uniform.
+type Teleport func(ids []uuid.UUID) []float64
+
+// Uniform jump to every link with the same probability, it give the global
+// PageRank.
+func Uniform() Teleport {
+	return nil
+}
+
+// Seeds jump to the seeds only with the same probability, it give the
+// PageRank personalized to the seeds, or the TrustRank when the seeds are
+// trusted links. unknown seed is ignored.
+func Seeds(seeds ...uuid.UUID) Teleport {
+	weights := make(map[uuid.UUID]float64, len(seeds))
+	for _, id := range seeds {
+		weights[id] = 1
+	}
+	return Weighted(weights)
+}
+
+// Weighted jump to the links of weights proportionally to their weight,
+// negative weight count as zero.
+func Weighted(weights map[uuid.UUID]float64) Teleport {
+	return func(ids []uuid.UUID) []float64 {
+		vector := make([]float64, len(ids))
+		for v, id := range ids {
+			if w := weights[id]; w > 0 {
+				vector[v] = w
+			}
+		}
+		return vector
+	}
+}
+
+// teleportVector return the normalized vector of t over the links of s, nil
+// for uniform.
+func teleportVector(t Teleport, s *graphsnap.Snapshot) ([]float64, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	vector := t(s.IDs)
+	total := graphsnap.Sum(vector)
+	if total <= 0 {
+		return nil, ErrNoSeeds
+	}
+	for v := range vector {
+		vector[v] /= total
+	}
+	return vector, nil
+}
@@ -0,0 +1,187 @@
+package linkstore
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odit-bit/linkstore/api"
+	"github.com/odit-bit/linkstore/linkgraph"
+	"github.com/odit-bit/linkstore/pagerank"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// mapping between linkgraph model and its wire representation, shared by
+// server and client so both side always agree.
+
+func linkToProto(link *linkgraph.Link) *api.Link {
+	return &api.Link{
+		Uuid:          link.ID[:],
+		Url:           link.URL,
+		RetrievedAt:   timeToProto(link.RetrievedAt),
+		StatusCode:    int32(link.StatusCode),
+		ContentType:   link.ContentType,
+		ContentLength: link.ContentLength,
+		ContentHash:   link.ContentHash,
+		Title:         link.Title,
+		LastModified:  timeToProto(link.LastModified),
+		Etag:          link.ETag,
+		OriginalUrl:   link.OriginalURL,
+		Pagerank:      link.PageRank,
+		Trustrank:     link.TrustRank,
+		Hub:           link.HubScore,
+		Authority:     link.AuthorityScore,
+	}
+}
+
+func linkFromProto(msg *api.Link) *linkgraph.Link {
+	return &linkgraph.Link{
+		ID:             uuidFromBytes(msg.Uuid),
+		URL:            msg.Url,
+		RetrievedAt:    timeFromProto(msg.RetrievedAt),
+		StatusCode:     int(msg.StatusCode),
+		ContentType:    msg.ContentType,
+		ContentLength:  msg.ContentLength,
+		ContentHash:    msg.ContentHash,
+		Title:          msg.Title,
+		LastModified:   timeFromProto(msg.LastModified),
+		ETag:           msg.Etag,
+		OriginalURL:    msg.OriginalUrl,
+		PageRank:       msg.Pagerank,
+		TrustRank:      msg.Trustrank,
+		HubScore:       msg.Hub,
+		AuthorityScore: msg.Authority,
+	}
+}
+
+func edgeToProto(edge *linkgraph.Edge) *api.Edge {
+	return &api.Edge{
+		Uuid:        edge.ID[:],
+		SrcUuid:     edge.Src[:],
+		DstUuid:     edge.Dst[:],
+		UpdatedAt:   timeToProto(edge.UpdateAt),
+		AnchorText:  edge.AnchorText,
+		Nofollow:    edge.Rel.Has(linkgraph.RelNofollow),
+		Sponsored:   edge.Rel.Has(linkgraph.RelSponsored),
+		Ugc:         edge.Rel.Has(linkgraph.RelUGC),
+		Boilerplate: edge.Boilerplate,
+		Occurrences: int64(edge.Occurrences),
+		FirstSeen:   timeToProto(edge.FirstSeen),
+		VanishedAt:  timeToProto(edge.VanishedAt),
+
+		DstAliasUuid: uuidToProto(edge.DstAlias),
+	}
+}
+
+func edgeFromProto(msg *api.Edge) *linkgraph.Edge {
+	edge := linkgraph.Edge{
+		ID:          uuidFromBytes(msg.Uuid),
+		Src:         uuidFromBytes(msg.SrcUuid),
+		Dst:         uuidFromBytes(msg.DstUuid),
+		UpdateAt:    timeFromProto(msg.UpdatedAt),
+		AnchorText:  msg.AnchorText,
+		Boilerplate: msg.Boilerplate,
+		Occurrences: int(msg.Occurrences),
+		FirstSeen:   timeFromProto(msg.FirstSeen),
+		VanishedAt:  timeFromProto(msg.VanishedAt),
+		DstAlias:    uuidFromBytes(msg.DstAliasUuid),
+	}
+	if msg.Nofollow {
+		edge.Rel |= linkgraph.RelNofollow
+	}
+	if msg.Sponsored {
+		edge.Rel |= linkgraph.RelSponsored
+	}
+	if msg.Ugc {
+		edge.Rel |= linkgraph.RelUGC
+	}
+	return &edge
+}
+
+func redirectToProto(r *linkgraph.Redirect) *api.Redirect {
+	return &api.Redirect{
+		AliasUuid:  r.Alias[:],
+		TargetUuid: r.Target[:],
+		StatusCode: int32(r.StatusCode),
+		ObservedAt: timeToProto(r.ObservedAt),
+	}
+}
+
+func redirectFromProto(msg *api.Redirect) *linkgraph.Redirect {
+	return &linkgraph.Redirect{
+		Alias:      uuidFromBytes(msg.AliasUuid),
+		Target:     uuidFromBytes(msg.TargetUuid),
+		StatusCode: int(msg.StatusCode),
+		ObservedAt: timeFromProto(msg.ObservedAt),
+	}
+}
+
+func scoreJobToProto(job *ScoreJob) *api.ScoreJob {
+	return &api.ScoreJob{
+		Uuid:       job.ID[:],
+		Kind:       api.ScoreKind(job.Kind),
+		State:      api.JobState(job.State),
+		Phase:      string(job.Progress.Phase),
+		Links:      int64(job.Progress.Links),
+		Edges:      int64(job.Progress.Edges),
+		Iterations: int32(job.Progress.Iterations),
+		Delta:      job.Progress.Delta,
+		Stored:     int64(job.Progress.Stored),
+		Error:      job.Err,
+		StartedAt:  timeToProto(job.StartedAt),
+		FinishedAt: timeToProto(job.FinishedAt),
+	}
+}
+
+func scoreJobFromProto(msg *api.ScoreJob) *ScoreJob {
+	return &ScoreJob{
+		ID:    uuidFromBytes(msg.Uuid),
+		Kind:  linkgraph.ScoreKind(msg.Kind),
+		State: JobState(msg.State),
+		Progress: pagerank.Progress{
+			Phase:      pagerank.Phase(msg.Phase),
+			Links:      int(msg.Links),
+			Edges:      int(msg.Edges),
+			Iterations: int(msg.Iterations),
+			Delta:      msg.Delta,
+			Stored:     int(msg.Stored),
+		},
+		Err:        msg.Error,
+		StartedAt:  timeFromProto(msg.StartedAt),
+		FinishedAt: timeFromProto(msg.FinishedAt),
+	}
+}
+
+// uuidToProto send uuid.Nil as empty bytes for optional ID.
+func uuidToProto(id uuid.UUID) []byte {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id[:]
+}
+
+func uuidFromBytes(b []byte) uuid.UUID {
+	if len(b) != 16 {
+		return uuid.Nil
+	}
+
+	var dst uuid.UUID
+	copy(dst[:], b)
+	return dst
+}
+
+// timeToProto convert t into timestamp, the zero time (e.g. link that is not
+// retrieved yet) is sent as nil so it survive the round trip.
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// timeFromProto is the inverse of timeToProto, the returned time is in UTC.
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -1,8 +1,11 @@
 package linkstore
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
@@ -15,19 +18,27 @@ import (
 	"github.com/odit-bit/linkstore/api"
 	"github.com/odit-bit/linkstore/linkgraph"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Server struct {
 	Port    int
 	Handler linkgraph.Graph
+
+	// Options is passed to the underlying grpc server, e.g. the stats handler
+	// that propagate trace context of incoming RPC down to the graph.
+	Options []grpc.ServerOption
+
+	// Normalizer is applied to url of incoming links, see WithURLNormalizer.
+	Normalizer *linkgraph.Normalizer
 }
 
 func (srv *Server) ListenAndServe() error {
-	linkServer := NewServer(srv.Handler)
+	linkServer := NewServer(srv.Handler, WithURLNormalizer(srv.Normalizer))
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(srv.Options...)
 	api.RegisterLinkGraphServer(grpcServer, linkServer)
 
 	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", srv.Port))
@@ -59,6 +70,7 @@ func (srv *Server) ListenAndServe() error {
 	}
 
 	grpcServer.GracefulStop()
+	linkServer.Close()
 
 	wg.Wait()
 	return nil
@@ -71,47 +83,89 @@ var _ api.LinkGraphServer = (*GraphServer)(nil)
 type GraphServer struct {
 	api.UnimplementedLinkGraphServer
 	g linkgraph.Graph
+
+	normalizer *linkgraph.Normalizer
+
+	jobs *scoreJobs
+}
+
+// GraphServerOption configure GraphServer.
+type GraphServerOption func(*GraphServer)
+
+// WithURLNormalizer normalize url of links received by the server before
+// they reach the graph, so every client get the same canonical url whatever
+// the graph backend. url that can't be normalized is rejected with
+// codes.InvalidArgument.
+func WithURLNormalizer(n *linkgraph.Normalizer) GraphServerOption {
+	return func(srv *GraphServer) {
+		srv.normalizer = n
+	}
 }
 
-func NewServer(graph linkgraph.Graph) *GraphServer {
+func NewServer(graph linkgraph.Graph, opts ...GraphServerOption) *GraphServer {
 
 	srv := GraphServer{
 		UnimplementedLinkGraphServer: api.UnimplementedLinkGraphServer{},
 		g:                            graph,
+		jobs:                         newScoreJobs(),
+	}
+	for _, opt := range opts {
+		opt(&srv)
 	}
 	return &srv
 }
 
+// Close cancel the running ComputeScores job and wait for it to stop, the
+// server can't start job anymore. it doesn't close the graph.
+func (srv *GraphServer) Close() {
+	srv.jobs.stop()
+}
+
 // Edges implements api.LinkGraphServer.
 func (srv *GraphServer) Edges(idRange *api.Range, w api.LinkGraph_EdgesServer) error {
-	updateBefore := idRange.Filter.AsTime()
+	updateBefore := timeFromProto(idRange.Filter)
 
-	from, err := uuid.FromBytes(idRange.FromUuid)
+	from, to, err := rangeFromProto(idRange)
 	if err != nil {
 		return err
 	}
-	to, err := uuid.FromBytes(idRange.ToUuid)
+	after, err := resumeFromProto(idRange.ResumeToken, edgeResumeTokenLen)
 	if err != nil {
 		return err
 	}
+	if after != nil {
+		// restart at the source of the last delivered edge, the edges up to
+		// the token are skipped below
+		from = maxUUID(from, uuidFromBytes(after[:16]))
+	}
 
-	it, err := srv.g.Edges(from, to, updateBefore)
+	// stream context is cancelled when the client goes away or the deadline
+	// is exceeded, which abort the underlying query
+	var opts []linkgraph.EdgeOption
+	if idRange.SkipNofollow {
+		opts = append(opts, linkgraph.SkipNofollow())
+	}
+	if idRange.AsOf != nil {
+		opts = append(opts, linkgraph.AsOf(timeFromProto(idRange.AsOf)))
+	}
+	if idRange.ResolveRedirects {
+		opts = append(opts, linkgraph.ResolveRedirects())
+	}
+
+	it, err := srv.g.Edges(w.Context(), from, to, updateBefore, opts...)
 	if err != nil {
-		_ = it.Close()
-		return err
+		return toStatus(err)
 	}
 	defer func() { _ = it.Close() }()
 
 	for it.Next() {
-		edge := it.Edge()
-		msg := &api.Edge{
-			Uuid:      edge.ID[:],
-			SrcUuid:   edge.Src[:],
-			DstUuid:   edge.Dst[:],
-			UpdatedAt: timestamppb.New(edge.UpdateAt),
+		edge := edgeToProto(it.Edge())
+		edge.ResumeToken = edgeResumeToken(it.Edge())
+		if after != nil && bytes.Compare(edge.ResumeToken, after) <= 0 {
+			continue
 		}
 
-		if err := w.Send(msg); err != nil {
+		if err := w.Send(edge); err != nil {
 			_ = it.Close()
 			return err
 		}
@@ -119,101 +173,472 @@ func (srv *GraphServer) Edges(idRange *api.Range, w api.LinkGraph_EdgesServer) e
 	}
 
 	if err := it.Error(); err != nil {
-		return err
+		return toStatus(err)
 	}
 
-	return it.Close()
+	return toStatus(it.Close())
+}
+
+// OutEdges implements api.LinkGraphServer.
+func (srv *GraphServer) OutEdges(req *api.ID, w api.LinkGraph_OutEdgesServer) error {
+	id, err := uuid.FromBytes(req.Uuid)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "link id: %v", err)
+	}
+
+	it, err := srv.g.OutEdges(w.Context(), id)
+	if err != nil {
+		return toStatus(err)
+	}
+	return sendEdges(it, w.Send)
+}
+
+// InEdges implements api.LinkGraphServer.
+func (srv *GraphServer) InEdges(req *api.ID, w api.LinkGraph_InEdgesServer) error {
+	id, err := uuid.FromBytes(req.Uuid)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "link id: %v", err)
+	}
+
+	it, err := srv.g.InEdges(w.Context(), id)
+	if err != nil {
+		return toStatus(err)
+	}
+	return sendEdges(it, w.Send)
+}
+
+// sendEdges stream every edge of it and close it.
+func sendEdges(it linkgraph.EdgeIterator, send func(*api.Edge) error) error {
+	defer func() { _ = it.Close() }()
+
+	for it.Next() {
+		if err := send(edgeToProto(it.Edge())); err != nil {
+			return err
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return toStatus(err)
+	}
+
+	return toStatus(it.Close())
 }
 
 // RemoveStaleEdges implements api.LinkGraphServer.
-func (srv *GraphServer) RemoveStaleEdges(ctx context.Context, req *api.RemoveStaleEdgesQuery) (*emptypb.Empty, error) {
-	updatedBefore := req.UpdatedBefore.AsTime() //ptypes.Timestamp(req.UpdatedBefore)
+func (srv *GraphServer) RemoveStaleEdges(ctx context.Context, req *api.RemoveStaleEdgesQuery) (*api.RemoveStaleEdgesResult, error) {
+	updatedBefore := timeFromProto(req.UpdatedBefore)
 
-	err := srv.g.RemoveStaleEdges(
+	var opts []linkgraph.RemoveOption
+	if req.DryRun {
+		opts = append(opts, linkgraph.DryRun())
+	}
+
+	removed, err := srv.g.RemoveStaleEdges(
+		ctx,
 		uuidFromBytes(req.FromUuid),
 		updatedBefore,
+		opts...,
 	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := api.RemoveStaleEdgesResult{
+		Removed:  removed.Count,
+		DstUuids: make([][]byte, len(removed.DstIDs)),
+	}
+	for i, id := range removed.DstIDs {
+		id := id
+		res.DstUuids[i] = id[:]
+	}
+	return &res, nil
+}
+
+// RemoveLink implements api.LinkGraphServer.
+func (srv *GraphServer) RemoveLink(ctx context.Context, req *api.ID) (*emptypb.Empty, error) {
+	id, err := uuid.FromBytes(req.Uuid)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "link id: %v", err)
+	}
+
+	if err := srv.g.RemoveLink(ctx, id); err != nil {
+		return nil, toStatus(err)
+	}
+	return new(empty.Empty), nil
+}
+
+// RemoveStaleLinks implements api.LinkGraphServer.
+func (srv *GraphServer) RemoveStaleLinks(ctx context.Context, req *api.RemoveLinksQuery) (*api.RemoveLinksResult, error) {
+	n, err := srv.g.RemoveStaleLinks(ctx, timeFromProto(req.RetrievedBefore))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &api.RemoveLinksResult{Removed: n}, nil
+}
+
+// RemoveOrphanLinks implements api.LinkGraphServer.
+func (srv *GraphServer) RemoveOrphanLinks(ctx context.Context, req *api.RemoveLinksQuery) (*api.RemoveLinksResult, error) {
+	n, err := srv.g.RemoveOrphanLinks(ctx, timeFromProto(req.RetrievedBefore))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &api.RemoveLinksResult{Removed: n}, nil
+}
+
+// LookupLink implements api.LinkGraphServer.
+func (srv *GraphServer) LookupLink(ctx context.Context, req *api.ID) (*api.Link, error) {
+	id, err := uuid.FromBytes(req.Uuid)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "link id: %v", err)
+	}
+
+	link, err := srv.g.LookupLink(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return linkToProto(link), nil
+}
+
+// LookupLinkByURL implements api.LinkGraphServer.
+func (srv *GraphServer) LookupLinkByURL(ctx context.Context, req *api.LookupLinkByURLQuery) (*api.Link, error) {
+	url, err := srv.normalizer.Normalize(req.Url)
+	if err != nil {
+		// there is no link with url that can't be normalized
+		return nil, toStatus(linkgraph.ErrNotFound)
+	}
+
+	link, err := srv.g.LookupLinkByURL(ctx, url)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return linkToProto(link), nil
+}
+
+// ResolveURLs implements api.LinkGraphServer.
+func (srv *GraphServer) ResolveURLs(ctx context.Context, req *api.ResolveURLsQuery) (*api.ResolveURLsResult, error) {
+	// the result is keyed by the requested urls
+	requested := srv.normalizer.NormalizeAll(req.Urls)
+	urls := make([]string, 0, len(requested))
+	for url := range requested {
+		urls = append(urls, url)
+	}
+
+	resolved, err := srv.g.ResolveURLs(ctx, urls)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := api.ResolveURLsResult{Uuids: make(map[string][]byte, len(req.Urls))}
+	for url, id := range resolved {
+		id := id
+		for _, rawURL := range requested[url] {
+			res.Uuids[rawURL] = id[:]
+		}
+	}
+	return &res, nil
+}
+
+// LookupEdge implements api.LinkGraphServer.
+func (srv *GraphServer) LookupEdge(ctx context.Context, req *api.ID) (*api.Edge, error) {
+	id, err := uuid.FromBytes(req.Uuid)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "edge id: %v", err)
+	}
+
+	edge, err := srv.g.LookupEdge(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
 
-	return new(empty.Empty), err
+	return edgeToProto(edge), nil
 }
 
 // UpsertEdge implements api.LinkGraphServer.
 func (srv *GraphServer) UpsertEdge(ctx context.Context, req *api.Edge) (*api.Edge, error) {
-	edge := linkgraph.Edge{
-		ID:  uuidFromBytes(req.Uuid),
-		Src: uuidFromBytes(req.SrcUuid),
-		Dst: uuidFromBytes(req.DstUuid),
+	edge := edgeFromProto(req)
+	if err := srv.g.UpsertEdge(ctx, edge); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return edgeToProto(edge), nil
+}
+
+// UpdateScores implements api.LinkGraphServer.
+func (srv *GraphServer) UpdateScores(ctx context.Context, req *api.UpdateScoresRequest) (*emptypb.Empty, error) {
+	if len(req.Uuids) != len(req.Scores) {
+		return nil, status.Errorf(codes.InvalidArgument, "%d link ids for %d scores", len(req.Uuids), len(req.Scores))
 	}
 
-	if err := srv.g.UpsertEdge(&edge); err != nil {
-		return nil, err
+	scores := make(map[uuid.UUID]float64, len(req.Uuids))
+	for i, b := range req.Uuids {
+		id, err := uuid.FromBytes(b)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "link id: %v", err)
+		}
+		scores[id] = req.Scores[i]
 	}
 
-	req.Uuid = edge.ID[:]
-	req.SrcUuid = edge.Src[:]
-	req.DstUuid = edge.Dst[:]
-	req.UpdatedAt = timestamppb.New(edge.UpdateAt)
-	return req, nil
+	if err := srv.g.UpdateScores(ctx, linkgraph.ScoreKind(req.Kind), scores); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
 }
 
-// UpsertLink implements api.LinkGraphServer.
-func (srv *GraphServer) UpsertLink(ctx context.Context, req *api.Link) (*api.Link, error) {
-	var (
-		err  error
-		link = linkgraph.Link{
-			ID:  uuidFromBytes(req.Uuid),
-			URL: req.Url,
+// ComputeScores implements api.LinkGraphServer.
+func (srv *GraphServer) ComputeScores(ctx context.Context, req *api.ComputeScoresRequest) (*api.ScoreJob, error) {
+	spec := ScoreJobSpec{
+		Kind:          linkgraph.ScoreKind(req.Kind),
+		DampingFactor: req.DampingFactor,
+		Tolerance:     req.Tolerance,
+		MaxIterations: int(req.MaxIterations),
+	}
+	for _, b := range req.SeedUuids {
+		id, err := uuid.FromBytes(b)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "seed id: %v", err)
 		}
-	)
+		spec.Seeds = append(spec.Seeds, id)
+	}
 
-	link.RetrievedAt = req.RetrievedAt.AsTime()
-	if err = srv.g.UpsertLink(&link); err != nil {
-		return nil, err
+	job, err := srv.jobs.start(srv.g, spec)
+	switch {
+	case errors.Is(err, ErrJobRunning):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, errInvalidJobSpec):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, toStatus(err)
 	}
 
-	req.RetrievedAt = timestamppb.New(link.RetrievedAt) //timeToProto(link.RetrievedAt)
-	req.Url = link.URL
-	req.Uuid = link.ID[:]
+	return scoreJobToProto(job), nil
+}
+
+// GetScoreJob implements api.LinkGraphServer.
+func (srv *GraphServer) GetScoreJob(ctx context.Context, req *api.ID) (*api.ScoreJob, error) {
+	id, err := uuid.FromBytes(req.Uuid)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "job id: %v", err)
+	}
 
-	return req, nil
+	job, ok := srv.jobs.get(id)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "score job %s not found", id)
+	}
+	return scoreJobToProto(job), nil
 }
 
-func (srv *GraphServer) Links(idRange *api.Range, w api.LinkGraph_LinksServer) error {
-	accessedBefore := idRange.Filter.AsTime()
+// UpsertRedirect implements api.LinkGraphServer.
+func (srv *GraphServer) UpsertRedirect(ctx context.Context, req *api.Redirect) (*api.Redirect, error) {
+	redirect := redirectFromProto(req)
+	if err := srv.g.UpsertRedirect(ctx, redirect); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return redirectToProto(redirect), nil
+}
 
-	from, err := uuid.FromBytes(idRange.FromUuid)
+// ResolveRedirect implements api.LinkGraphServer.
+func (srv *GraphServer) ResolveRedirect(ctx context.Context, req *api.ID) (*api.RedirectChain, error) {
+	id, err := uuid.FromBytes(req.Uuid)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "link id: %v", err)
+	}
+
+	chain, err := srv.g.ResolveRedirect(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := api.RedirectChain{Redirects: make([]*api.Redirect, len(chain))}
+	for i, r := range chain {
+		res.Redirects[i] = redirectToProto(r)
+	}
+	return &res, nil
+}
+
+// UpsertLinks implements api.LinkGraphServer.
+func (srv *GraphServer) UpsertLinks(stream api.LinkGraph_UpsertLinksServer) error {
+	var links []*linkgraph.Link
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		links = append(links, linkFromProto(req))
+	}
+
+	// links rejected by the normalizer don't reach the graph
+	errs := make([]error, len(links))
+	var valid []*linkgraph.Link
+	for i, link := range links {
+		if errs[i] = srv.normalizer.NormalizeLink(link); errs[i] == nil {
+			valid = append(valid, link)
+		}
+	}
+
+	err := srv.g.UpsertLinks(stream.Context(), valid)
+	var batchErr *linkgraph.BatchError
+	switch {
+	case errors.As(err, &batchErr):
+		j := 0
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = batchErr.Errs[j]
+				j++
+			}
+		}
+	case err != nil:
+		return toStatus(err)
+	}
+
+	batchErrs, err := batchErrorsToProto(linkgraph.NewBatchError(errs))
 	if err != nil {
 		return err
 	}
-	to, err := uuid.FromBytes(idRange.ToUuid)
+
+	res := api.UpsertLinksResult{
+		Links:  make([]*api.Link, len(links)),
+		Errors: batchErrs,
+	}
+	for i, link := range links {
+		res.Links[i] = linkToProto(link)
+	}
+	return stream.SendAndClose(&res)
+}
+
+// UpsertEdges implements api.LinkGraphServer.
+func (srv *GraphServer) UpsertEdges(stream api.LinkGraph_UpsertEdgesServer) error {
+	var edges []*linkgraph.Edge
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		edges = append(edges, edgeFromProto(req))
+	}
+
+	batchErrs, err := batchErrorsToProto(srv.g.UpsertEdges(stream.Context(), edges))
 	if err != nil {
 		return err
 	}
 
-	it, err := srv.g.Links(from, to, accessedBefore)
+	res := api.UpsertEdgesResult{
+		Edges:  make([]*api.Edge, len(edges)),
+		Errors: batchErrs,
+	}
+	for i, edge := range edges {
+		res.Edges[i] = edgeToProto(edge)
+	}
+	return stream.SendAndClose(&res)
+}
+
+// UpsertLink implements api.LinkGraphServer.
+func (srv *GraphServer) UpsertLink(ctx context.Context, req *api.Link) (*api.Link, error) {
+	link := linkFromProto(req)
+	if err := srv.normalizer.NormalizeLink(link); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := srv.g.UpsertLink(ctx, link); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return linkToProto(link), nil
+}
+
+func (srv *GraphServer) Links(idRange *api.Range, w api.LinkGraph_LinksServer) error {
+	accessedBefore := timeFromProto(idRange.Filter)
+
+	from, to, err := rangeFromProto(idRange)
+	if err != nil {
+		return err
+	}
+	after, err := resumeFromProto(idRange.ResumeToken, linkResumeTokenLen)
 	if err != nil {
 		return err
 	}
+	if after != nil {
+		from = maxUUID(from, uuidFromBytes(after))
+	}
+
+	it, err := srv.g.Links(w.Context(), from, to, accessedBefore)
+	if err != nil {
+		return toStatus(err)
+	}
 
 	defer func() { _ = it.Close() }()
 
 	for it.Next() {
-		link := it.Link()
-		msg := api.Link{
-			Uuid:        link.ID[:],
-			Url:         link.URL,
-			RetrievedAt: timestamppb.New(link.RetrievedAt),
+		link := linkToProto(it.Link())
+		link.ResumeToken = link.Uuid
+		if after != nil && bytes.Compare(link.ResumeToken, after) <= 0 {
+			continue
 		}
 
-		if err := w.Send(&msg); err != nil {
+		if err := w.Send(link); err != nil {
 			_ = it.Close()
 			return err
 		}
 	}
 
 	if err := it.Error(); err != nil {
-		return err
+		return toStatus(err)
 	}
 
-	return it.Close()
+	return toStatus(it.Close())
+}
+
+// rangeFromProto validate and return [from, to) of the range.
+func rangeFromProto(idRange *api.Range) (from, to uuid.UUID, err error) {
+	from, err = uuid.FromBytes(idRange.FromUuid)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Errorf(codes.InvalidArgument, "range from: %v", err)
+	}
+	to, err = uuid.FromBytes(idRange.ToUuid)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Errorf(codes.InvalidArgument, "range to: %v", err)
+	}
+	return from, to, nil
+}
+
+// resume token of streamed item is its position in the iteration order, the
+// link ID for links and the source followed by destination ID for edges. the
+// destination of edge rewritten by redirects is its alias.
+const (
+	linkResumeTokenLen = 16
+	edgeResumeTokenLen = 32
+)
+
+func edgeResumeToken(edge *linkgraph.Edge) []byte {
+	token := make([]byte, 0, edgeResumeTokenLen)
+	token = append(token, edge.Src[:]...)
+	if edge.DstAlias != uuid.Nil {
+		return append(token, edge.DstAlias[:]...)
+	}
+	return append(token, edge.Dst[:]...)
+}
+
+// resumeFromProto validate the resume token of range, nil if there is none.
+func resumeFromProto(token []byte, size int) ([]byte, error) {
+	if len(token) == 0 {
+		return nil, nil
+	}
+	if len(token) != size {
+		return nil, status.Errorf(codes.InvalidArgument, "resume token: expect %d bytes, got %d", size, len(token))
+	}
+	return token, nil
+}
+
+func maxUUID(a, b uuid.UUID) uuid.UUID {
+	if bytes.Compare(a[:], b[:]) < 0 {
+		return b
+	}
+	return a
 }
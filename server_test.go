@@ -19,7 +19,10 @@ import (
 
 func Test_server(t *testing.T) {
 
-	db := linkpostgre.New(connectPG())
+	db, err := linkpostgre.New(connectPG())
+	if err != nil {
+		t.Fatal(err)
+	}
 	linkServer := NewServer(db)
 
 	grpcServer := grpc.NewServer()
@@ -52,7 +55,7 @@ func Test_server(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	cli, err := NewClient(ctx, conn)
+	cli, err := NewClient(conn)
 	if err != nil {
 		cancel()
 		t.Error(err)
@@ -65,7 +68,7 @@ func Test_server(t *testing.T) {
 		// RetrievedAt: time.Now(),
 	}
 
-	if err := cli.UpsertLink(l); err != nil {
+	if err := cli.UpsertLink(ctx, l); err != nil {
 		cancel()
 		t.Fatal(err)
 	}
@@ -10,13 +10,19 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 	"github.com/odit-bit/linkstore"
+	"github.com/odit-bit/linkstore/hits"
+	"github.com/odit-bit/linkstore/linkgraph"
 	"github.com/odit-bit/linkstore/linkpostgre"
+	"github.com/odit-bit/linkstore/pagerank"
 	"github.com/uptrace/opentelemetry-go-extra/otelsqlx"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -43,7 +49,65 @@ func main() {
 		slog.Error(err.Error())
 		os.Exit(2)
 	}
-	db := linkpostgre.New(dbConn)
+	// NORMALIZE_URLS enable url normalization, tracking parameters are
+	// stripped as well
+	var normalizer *linkgraph.Normalizer
+	if _, ok := os.LookupEnv("NORMALIZE_URLS"); ok {
+		normalizer = linkgraph.NewNormalizer(linkgraph.StripParams(linkgraph.TrackingParams...))
+	}
+
+	pgOpts := []linkpostgre.Option{linkpostgre.WithURLNormalizer(normalizer)}
+
+	// LINK_ID_NAMESPACE enable name-based link IDs under the namespace
+	if ns, ok := os.LookupEnv("LINK_ID_NAMESPACE"); ok {
+		namespace, err := uuid.Parse(ns)
+		if err != nil {
+			slog.Error("invalid LINK_ID_NAMESPACE", "err", err)
+			os.Exit(2)
+		}
+		pgOpts = append(pgOpts, linkpostgre.WithNameBasedIDs(namespace))
+	}
+
+	db, err := linkpostgre.New(dbConn, pgOpts...)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(2)
+	}
+
+	// merge-urls apply the normalizer to the links already stored and exit,
+	// writers must be stopped while it run
+	if len(os.Args) > 1 && os.Args[1] == "merge-urls" {
+		res, err := db.MergeDuplicateURLs(mainCtx)
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		slog.Info("merge duplicate urls", "rewritten", res.Rewritten, "merged", res.Merged)
+		return
+	}
+
+	// pagerank compute the PageRank of every link, store it and exit
+	if len(os.Args) > 1 && os.Args[1] == "pagerank" {
+		res, err := pagerank.New(db).Run(mainCtx)
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		slog.Info("pagerank", "links", res.Links, "edges", res.Edges, "iterations", res.Iterations, "converged", res.Converged)
+		return
+	}
+
+	// hits compute the hub and authority scores of every link, store them
+	// and exit
+	if len(os.Args) > 1 && os.Args[1] == "hits" {
+		res, err := hits.New(db).Run(mainCtx)
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		slog.Info("hits", "links", res.Links, "edges", res.Edges, "iterations", res.Iterations, "converged", res.Converged)
+		return
+	}
 
 	//setup exporter connection
 
@@ -78,10 +142,12 @@ func main() {
 		}
 	}()
 
-	// setup service server
+	// setup service server, the otel handler start span for incoming RPC so
+	// otelsqlx spans of the query are recorded as its children
 	srv := linkstore.Server{
 		Port:    8181,
 		Handler: db,
+		Options: []grpc.ServerOption{grpc.StatsHandler(otelgrpc.NewServerHandler())},
 	}
 
 	err = srv.ListenAndServe()
@@ -0,0 +1,111 @@
+package linkstore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/odit-bit/linkstore/api"
+	"github.com/odit-bit/linkstore/linkgraph"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus convert error returned by the graph into grpc status error, so
+// the client can recover the linkgraph error on the other side of the wire.
+func toStatus(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, linkgraph.ErrNotFound):
+		return status.Error(codes.NotFound, linkgraph.ErrNotFound.Error())
+	case errors.Is(err, linkgraph.ErrUnknownEdgeLinks):
+		return status.Error(codes.FailedPrecondition, linkgraph.ErrUnknownEdgeLinks.Error())
+	case errors.Is(err, linkgraph.ErrRedirectLoop):
+		return status.Error(codes.FailedPrecondition, linkgraph.ErrRedirectLoop.Error())
+	case errors.Is(err, linkgraph.ErrInvalidURL):
+		return status.Error(codes.InvalidArgument, linkgraph.ErrInvalidURL.Error())
+	case errors.Is(err, linkgraph.ErrLinkIDMismatch):
+		return status.Error(codes.InvalidArgument, linkgraph.ErrLinkIDMismatch.Error())
+	case errors.Is(err, linkgraph.ErrUnknownScoreKind):
+		return status.Error(codes.InvalidArgument, linkgraph.ErrUnknownScoreKind.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// fromStatus is the inverse of toStatus, any status that does not represent
+// a linkgraph error is returned as is.
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		if st.Message() == linkgraph.ErrNotFound.Error() {
+			return linkgraph.ErrNotFound
+		}
+	case codes.FailedPrecondition:
+		switch st.Message() {
+		case linkgraph.ErrUnknownEdgeLinks.Error():
+			return linkgraph.ErrUnknownEdgeLinks
+		case linkgraph.ErrRedirectLoop.Error():
+			return linkgraph.ErrRedirectLoop
+		}
+	case codes.InvalidArgument:
+		switch st.Message() {
+		case linkgraph.ErrInvalidURL.Error():
+			return linkgraph.ErrInvalidURL
+		case linkgraph.ErrLinkIDMismatch.Error():
+			return linkgraph.ErrLinkIDMismatch
+		case linkgraph.ErrUnknownScoreKind.Error():
+			return linkgraph.ErrUnknownScoreKind
+		}
+	}
+
+	return err
+}
+
+// batchErrorsToProto split err of batch operation into the per-item errors
+// and the error that failed the whole batch.
+func batchErrorsToProto(err error) ([]*api.BatchError, error) {
+	var batchErr *linkgraph.BatchError
+	if !errors.As(err, &batchErr) {
+		return nil, toStatus(err)
+	}
+
+	var msgs []*api.BatchError
+	for i, itemErr := range batchErr.Errs {
+		if itemErr == nil {
+			continue
+		}
+
+		st, _ := status.FromError(toStatus(itemErr))
+		msgs = append(msgs, &api.BatchError{
+			Index:   int64(i),
+			Code:    int32(st.Code()),
+			Message: st.Message(),
+		})
+	}
+	return msgs, nil
+}
+
+// batchErrorFromProto is the inverse of batchErrorsToProto for a batch of
+// size n.
+func batchErrorFromProto(n int, msgs []*api.BatchError) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	errs := make([]error, n)
+	for _, msg := range msgs {
+		if msg.Index < 0 || msg.Index >= int64(n) {
+			continue
+		}
+		errs[msg.Index] = fromStatus(status.Error(codes.Code(msg.Code), msg.Message))
+	}
+	return linkgraph.NewBatchError(errs)
+}